@@ -0,0 +1,105 @@
+package errorist
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+// AtomicFile is a file replaced atomically on Commit, so readers never see partially written contents.
+// Contents are written to a temporary file in the same directory, which is synced and renamed
+// to the path on Commit. Close discards the temporary file unless committed, so it can be used
+// with CloseWithErrCapture in `defer`:
+//
+//	f, err := errorist.CreateAtomicFile("config.yaml", 0644)
+//	if err != nil {
+//	    return err
+//	}
+//	defer errorist.CloseWithErrCapture(f, &err)
+//	if _, err := f.Write(contents); err != nil {
+//	    return err
+//	}
+//	return f.Commit()
+//
+// It is not safe for concurrent use.
+type AtomicFile struct {
+	path string
+	tmp  *os.File
+	done bool
+}
+
+// CreateAtomicFile creates a temporary file which will be renamed to the path on Commit.
+func CreateAtomicFile(path string, perm os.FileMode) (*AtomicFile, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return nil, errors.WithMessagef(err, "create atomic file %s", path)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return nil, errors.WithMessagef(joinErrors(err, closeAndRemove(tmp)), "create atomic file %s", path)
+	}
+	return &AtomicFile{path: path, tmp: tmp}, nil
+}
+
+// Name returns the path where the file will be committed.
+func (f *AtomicFile) Name() string {
+	return f.path
+}
+
+func (f *AtomicFile) Write(p []byte) (int, error) {
+	return f.tmp.Write(p)
+}
+
+// Commit syncs the written contents and renames the temporary file to the path.
+// If it fails before the rename, the temporary file is discarded and the path is left unchanged.
+// If only syncing the directory fails, the path has already been replaced, but the rename may not
+// survive a crash; the error says so.
+func (f *AtomicFile) Commit() error {
+	if f.done {
+		return errors.WithMessagef(os.ErrClosed, "commit %s", f.path)
+	}
+	f.done = true
+
+	if err := f.tmp.Sync(); err != nil {
+		return errors.WithMessagef(joinErrors(err, closeAndRemove(f.tmp)), "commit %s", f.path)
+	}
+	if err := f.tmp.Close(); err != nil {
+		return errors.WithMessagef(joinErrors(err, closeAndRemove(f.tmp)), "commit %s", f.path)
+	}
+	if err := os.Rename(f.tmp.Name(), f.path); err != nil {
+		return errors.WithMessagef(joinErrors(err, closeAndRemove(f.tmp)), "commit %s", f.path)
+	}
+	if err := syncDir(filepath.Dir(f.path)); err != nil {
+		return errors.WithMessagef(err, "commit %s: renamed, but failed to sync the directory", f.path)
+	}
+	return nil
+}
+
+// Close discards the temporary file unless committed. It does nothing after Commit.
+func (f *AtomicFile) Close() error {
+	if f.done {
+		return nil
+	}
+	f.done = true
+	if err := closeAndRemove(f.tmp); err != nil {
+		return errors.WithMessagef(err, "discard %s", f.path)
+	}
+	return nil
+}
+
+// syncDir syncs the directory, so a rename in it is persisted.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		// directories can't be synced on Windows
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil {
+		return joinErrors(err, d.Close())
+	}
+	return d.Close()
+}
@@ -0,0 +1,106 @@
+package errorist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pkgErrors "github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAtomicFile(t *testing.T) {
+	Convey("Writing an errorist.AtomicFile", t, func() {
+		dir, err := os.MkdirTemp("", "errorist-test-*")
+		So(err, ShouldBeNil)
+		Reset(func() { _ = os.RemoveAll(dir) })
+		path := filepath.Join(dir, "config.yaml")
+
+		write := func(contents string, commit bool) (err error) {
+			f, err := CreateAtomicFile(path, 0640)
+			if err != nil {
+				return err
+			}
+			defer CloseWithErrCapture(f, &err)
+			if _, err := f.Write([]byte(contents)); err != nil {
+				return err
+			}
+			if !commit {
+				return nil
+			}
+			return f.Commit()
+		}
+
+		Convey("It should replace the file on Commit", func() {
+			So(write("old", true), ShouldBeNil)
+			So(write("new", true), ShouldBeNil)
+
+			contents, err := os.ReadFile(path)
+			So(err, ShouldBeNil)
+			So(string(contents), ShouldEqual, "new")
+
+			info, err := os.Stat(path)
+			So(err, ShouldBeNil)
+			So(info.Mode().Perm(), ShouldEqual, os.FileMode(0640))
+			So(entriesOf(dir), ShouldResemble, []string{"config.yaml"})
+		})
+
+		Convey("It should discard contents on Close unless committed", func() {
+			So(write("old", true), ShouldBeNil)
+			So(write("new", false), ShouldBeNil)
+
+			contents, err := os.ReadFile(path)
+			So(err, ShouldBeNil)
+			So(string(contents), ShouldEqual, "old")
+			So(entriesOf(dir), ShouldResemble, []string{"config.yaml"})
+		})
+
+		Convey("It should discard contents and report errors with context on failed Commit", func() {
+			So(os.MkdirAll(filepath.Join(path, "child"), 0700), ShouldBeNil)
+
+			err := write("new", true)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, "commit "+path+": rename ")
+			So(entriesOf(dir), ShouldResemble, []string{"config.yaml"})
+		})
+
+		Convey("It should create the temporary file next to relative paths", func() {
+			wd, err := os.Getwd()
+			So(err, ShouldBeNil)
+			So(os.Chdir(dir), ShouldBeNil)
+			Reset(func() { _ = os.Chdir(wd) })
+
+			f, err := CreateAtomicFile("relative.yaml", 0600)
+			So(err, ShouldBeNil)
+			entries := entriesOf(dir)
+			So(entries, ShouldHaveLength, 1)
+			So(entries[0], ShouldStartWith, ".relative.yaml.tmp-")
+
+			So(f.Commit(), ShouldBeNil)
+			So(entriesOf(dir), ShouldResemble, []string{"relative.yaml"})
+		})
+
+		Convey("It should not be committed twice", func() {
+			f, err := CreateAtomicFile(path, 0600)
+			So(err, ShouldBeNil)
+			So(f.Commit(), ShouldBeNil)
+			So(pkgErrors.Is(f.Commit(), os.ErrClosed), ShouldBeTrue)
+			So(f.Close(), ShouldBeNil)
+		})
+
+		Convey("It should report errors on creating with context", func() {
+			_, err := CreateAtomicFile(filepath.Join(dir, "not-exist", "config.yaml"), 0600)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, "create atomic file ")
+		})
+	})
+}
+
+func entriesOf(dir string) (names []string) {
+	entries, err := os.ReadDir(dir)
+	So(err, ShouldBeNil)
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
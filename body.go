@@ -0,0 +1,55 @@
+package errorist
+
+import (
+	"io"
+	"net/http"
+)
+
+// DrainError is an error occurred while draining an HTTP response body before closing it.
+// It is distinguished from errors on closing, which can be checked with errors.As.
+type DrainError struct {
+	Err error
+}
+
+func (e *DrainError) Error() string {
+	return "drain response body: " + e.Err.Error()
+}
+
+func (e *DrainError) Unwrap() error {
+	return e.Err
+}
+
+// CloseBodyWithErrCapture is same as CloseWithErrCapture, but for HTTP response bodies.
+// The body is drained up to BodyDrainLimit before closing, so the connection can be reused.
+// Errors while draining are reported as *DrainError, joined with one on closing if any.
+// It does nothing if the response or its body is nil.
+func CloseBodyWithErrCapture(resp *http.Response, capture *error, opts ...Option) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	opt := applyOptions(1, opts)
+	if err := drainAndClose(resp.Body, opt); err != nil && *capture == nil {
+		*capture = maybeWrap(err, opt)
+	}
+}
+
+// CloseBodyWithLogOnErr is same as CloseWithLogOnErr, but for HTTP response bodies.
+// Please refer CloseBodyWithErrCapture for details.
+func CloseBodyWithLogOnErr(resp *http.Response, opts ...Option) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	opt := applyOptions(1, opts)
+	if err := drainAndClose(resp.Body, opt); err != nil {
+		logError(maybeWrap(err, opt), newLogMeta(OperationClose, resp.Body, opt), opt)
+	}
+}
+
+// drainAndClose drains the body up to BodyDrainLimit and closes it.
+func drainAndClose(body io.ReadCloser, opts Options) error {
+	var drainErr error
+	if _, err := io.CopyN(io.Discard, body, opts.BodyDrainLimit); err != nil && err != io.EOF {
+		drainErr = &DrainError{Err: err}
+	}
+	return joinErrors(drainErr, body.Close())
+}
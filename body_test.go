@@ -0,0 +1,143 @@
+package errorist
+
+import (
+	stdlibErrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"strings"
+	"testing"
+
+	pkgErrors "github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCloseBodyWithErrCapture(t *testing.T) {
+	Convey("Calling errorist.CloseBodyWithErrCapture", t, func() {
+		var actualErr error
+
+		Convey("It should drain the body before closing", func() {
+			body := &bodyMock{Reader: strings.NewReader("remaining")}
+			CloseBodyWithErrCapture(&http.Response{Body: body}, &actualErr)
+
+			So(actualErr, ShouldBeNil)
+			So(body.CloseCalled, ShouldEqual, 1)
+			So(body.BytesRead, ShouldEqual, len("remaining"))
+		})
+
+		Convey("It should drain up to BodyDrainLimit", func() {
+			body := &bodyMock{Reader: strings.NewReader("remaining")}
+			CloseBodyWithErrCapture(&http.Response{Body: body}, &actualErr, WithBodyDrainLimit(3))
+
+			So(actualErr, ShouldBeNil)
+			So(body.CloseCalled, ShouldEqual, 1)
+			So(body.BytesRead, ShouldEqual, 3)
+		})
+
+		Convey("It should report drain errors separately from close errors", func() {
+			readErr := pkgErrors.New("read failed")
+			closeErr := pkgErrors.New("close failed")
+
+			CloseBodyWithErrCapture(&http.Response{Body: &bodyMock{Reader: &failingReader{readErr}}}, &actualErr, Wrapf("closing body"))
+			var drainErr *DrainError
+			So(stdlibErrors.As(actualErr, &drainErr), ShouldBeTrue)
+			So(stdlibErrors.Is(actualErr, readErr), ShouldBeTrue)
+			So(actualErr.Error(), ShouldEqual, "closing body: drain response body: read failed")
+
+			actualErr = nil
+			CloseBodyWithErrCapture(&http.Response{Body: &bodyMock{Reader: strings.NewReader(""), ReturnError: closeErr}}, &actualErr)
+			So(stdlibErrors.As(actualErr, &drainErr), ShouldBeFalse)
+			So(actualErr, ShouldEqual, closeErr)
+
+			actualErr = nil
+			CloseBodyWithErrCapture(&http.Response{Body: &bodyMock{Reader: &failingReader{readErr}, ReturnError: closeErr}}, &actualErr)
+			So(stdlibErrors.Is(actualErr, readErr), ShouldBeTrue)
+			So(stdlibErrors.Is(actualErr, closeErr), ShouldBeTrue)
+		})
+
+		Convey("It should not capture error if underlying error is already present", func() {
+			actualErr = pkgErrors.New("already present")
+			CloseBodyWithErrCapture(&http.Response{Body: &bodyMock{Reader: strings.NewReader(""), ReturnError: io.ErrClosedPipe}}, &actualErr)
+			So(actualErr, ShouldBeError, "already present")
+		})
+
+		Convey("It should do nothing with nil responses", func() {
+			So(func() {
+				CloseBodyWithErrCapture(nil, &actualErr)
+				CloseBodyWithErrCapture(&http.Response{}, &actualErr)
+			}, ShouldNotPanic)
+			So(actualErr, ShouldBeNil)
+		})
+
+		Convey("It should let the connection be reused", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("unread body"))
+			}))
+			defer server.Close()
+
+			var reused []bool
+			for i := 0; i < 2; i++ {
+				trace := &httptrace.ClientTrace{
+					GotConn: func(info httptrace.GotConnInfo) { reused = append(reused, info.Reused) },
+				}
+				req, _ := http.NewRequest("GET", server.URL, nil)
+				resp, err := server.Client().Do(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+				So(err, ShouldBeNil)
+				CloseBodyWithErrCapture(resp, &actualErr)
+			}
+			So(actualErr, ShouldBeNil)
+			So(reused, ShouldResemble, []bool{false, true})
+		})
+	})
+}
+
+func TestCloseBodyWithLogOnErr(t *testing.T) {
+	Convey("Calling errorist.CloseBodyWithLogOnErr", t, func() {
+		logger := &errorLoggerMock{}
+
+		Convey("It should log errors on draining or closing", func() {
+			body := &bodyMock{Reader: &failingReader{pkgErrors.New("read failed")}}
+			CloseBodyWithLogOnErr(&http.Response{Body: body}, WithErrorLogger(logger))
+
+			So(body.CloseCalled, ShouldEqual, 1)
+			So(logger.Entries(), ShouldHaveLength, 1)
+			entry := logger.Entries()[0]
+			So(entry.err.Error(), ShouldEqual, "drain response body: read failed")
+			So(entry.meta.Operation, ShouldEqual, OperationClose)
+			So(entry.meta.ResourceType, ShouldEqual, "*errorist.bodyMock")
+			So(entry.meta.Caller.Function, ShouldStartWith, "github.com/therne/errorist.TestCloseBodyWithLogOnErr")
+		})
+
+		Convey("It should do nothing with nil responses", func() {
+			So(func() { CloseBodyWithLogOnErr(nil, WithErrorLogger(logger)) }, ShouldNotPanic)
+			So(logger.Entries(), ShouldBeEmpty)
+		})
+	})
+}
+
+type bodyMock struct {
+	io.Reader
+	ReturnError error
+	BytesRead   int
+	CloseCalled int
+}
+
+func (b *bodyMock) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	b.BytesRead += n
+	return n, err
+}
+
+func (b *bodyMock) Close() error {
+	b.CloseCalled++
+	return b.ReturnError
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -1,6 +1,9 @@
 package errorist
 
-import "io"
+import (
+	"context"
+	"io"
+)
 
 // CloseWithErrCapture is used if you want to close and fail the function or
 // method on a `io.Closer.Close()` error (make sure the `error` return argument is
@@ -8,7 +11,7 @@ import "io"
 // will append the error caused by `Close` if any.
 func CloseWithErrCapture(c io.Closer, capture *error, opts ...Option) {
 	if err := c.Close(); err != nil && *capture == nil {
-		*capture = maybeWrap(err, applyOptions(opts))
+		*capture = maybeWrap(err, applyOptions(1, opts))
 	}
 }
 
@@ -18,7 +21,7 @@ func CloseWithErrCapture(c io.Closer, capture *error, opts ...Option) {
 // will send the error to the given channel caused by `Close` if any.
 func CloseWithErrChan(c io.Closer, errChan chan<- error, opts ...Option) {
 	if err := c.Close(); err != nil {
-		errChan <- maybeWrap(err, applyOptions(opts))
+		errChan <- maybeWrap(err, applyOptions(1, opts))
 	}
 }
 
@@ -28,7 +31,29 @@ func CloseWithErrChan(c io.Closer, errChan chan<- error, opts ...Option) {
 // will log the error caused by `Close` if any.
 func CloseWithLogOnErr(c io.Closer, opts ...Option) {
 	if err := c.Close(); err != nil {
-		opt := applyOptions(opts)
-		opt.Logger(maybeWrap(err, opt).Error())
+		opt := applyOptions(1, opts)
+		logError(maybeWrap(err, opt), newLogMeta(OperationClose, c, opt), opt)
+	}
+}
+
+// CloseWithErrCaptureCtx is same as CloseWithErrCapture, with options carried by the context.
+func CloseWithErrCaptureCtx(ctx context.Context, c io.Closer, capture *error, opts ...Option) {
+	if err := c.Close(); err != nil && *capture == nil {
+		*capture = maybeWrap(err, applyOptions(1, withContextOptions(ctx, opts)))
+	}
+}
+
+// CloseWithErrChanCtx is same as CloseWithErrChan, with options carried by the context.
+func CloseWithErrChanCtx(ctx context.Context, c io.Closer, errChan chan<- error, opts ...Option) {
+	if err := c.Close(); err != nil {
+		errChan <- maybeWrap(err, applyOptions(1, withContextOptions(ctx, opts)))
+	}
+}
+
+// CloseWithLogOnErrCtx is same as CloseWithLogOnErr, with options carried by the context.
+func CloseWithLogOnErrCtx(ctx context.Context, c io.Closer, opts ...Option) {
+	if err := c.Close(); err != nil {
+		opt := applyOptions(1, withContextOptions(ctx, opts))
+		logError(maybeWrap(err, opt), newLogMeta(OperationClose, c, opt), opt)
 	}
 }
@@ -0,0 +1,29 @@
+package errorist
+
+import "context"
+
+type optionsContextKey struct{}
+
+// WithOptions returns a copy of ctx carrying given options, which are applied on functions
+// end with "Ctx" called with the context. Options on the context override package-level options,
+// and can be overridden by call options. Options on the parent context are inherited.
+func WithOptions(ctx context.Context, opts ...Option) context.Context {
+	return context.WithValue(ctx, optionsContextKey{}, withContextOptions(ctx, opts))
+}
+
+// ContextOptions returns options carried by the context.
+func ContextOptions(ctx context.Context) []Option {
+	opts, _ := ctx.Value(optionsContextKey{}).([]Option)
+	return copyOptions(opts)
+}
+
+// withContextOptions returns options on the context followed by given options.
+func withContextOptions(ctx context.Context, opts []Option) []Option {
+	return append(ContextOptions(ctx), opts...)
+}
+
+// EffectiveOptionsCtx returns options applied on errorist functions called in current package
+// with the context. It is useful for debugging.
+func EffectiveOptionsCtx(ctx context.Context, opts ...Option) Options {
+	return applyOptions(1, withContextOptions(ctx, opts))
+}
@@ -0,0 +1,62 @@
+package errorist
+
+import (
+	"context"
+	"testing"
+
+	pkgErrors "github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWithOptions(t *testing.T) {
+	defer SetPackageLevelOptions()
+
+	Convey("Calling functions with options on context", t, func() {
+		SetPackageLevelOptions(Wrapf("package"), WithMaxFrames(1))
+		ctx := WithOptions(context.Background(), Wrapf("context"))
+
+		Convey("It should override package-level options", func() {
+			var err error
+			CloseWithErrCaptureCtx(ctx, &closerMock{ReturnError: pkgErrors.New("test")}, &err)
+			So(err, ShouldBeError, "context: test")
+			So(EffectiveOptionsCtx(ctx).MaxFrames, ShouldEqual, 1)
+		})
+
+		Convey("It should be overridden by call options", func() {
+			errChan := make(chan error, 1)
+			StopWithErrChanCtx(ctx, &stopperMock{ReturnError: pkgErrors.New("test")}, errChan, Wrapf("call"))
+			So(<-errChan, ShouldBeError, "call: test")
+		})
+
+		Convey("It should inherit options from parent context", func() {
+			child := WithOptions(ctx, WithMaxFrames(2))
+			So(EffectiveOptionsCtx(child).WrapArguments, ShouldResemble, []interface{}{"context"})
+			So(EffectiveOptionsCtx(child).MaxFrames, ShouldEqual, 2)
+			So(EffectiveOptionsCtx(ctx).MaxFrames, ShouldEqual, 1)
+		})
+
+		Convey("It should log with the logger on context", func() {
+			logger := &loggerMock{}
+			ctx := WithOptions(ctx, WithLogHandler(logger.Log))
+
+			CloseWithLogOnErrCtx(ctx, &closerMock{ReturnError: pkgErrors.New("test")})
+			StopWithErrLogCtx(ctx, &stopperMock{ReturnError: pkgErrors.New("test")})
+			So(logger.Logs(), ShouldResemble, []string{"context: test", "context: test"})
+		})
+
+		Convey("It should be applied on recovered panics", func() {
+			errChan := make(chan error, 1)
+			func() {
+				defer RecoverWithErrChanCtx(ctx, errChan)
+				panic("boom")
+			}()
+			So((<-errChan).Error(), ShouldStartWith, "context: panic: boom")
+		})
+
+		Convey("Without options, it should behave same as functions without context", func() {
+			var err error
+			CloseWithErrCaptureCtx(context.Background(), &closerMock{ReturnError: pkgErrors.New("test")}, &err)
+			So(err, ShouldBeError, "package: test")
+		})
+	})
+}
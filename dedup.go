@@ -0,0 +1,104 @@
+package errorist
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var logDedup = newDeduper(func(d time.Duration, f func()) { time.AfterFunc(d, f) })
+
+// deduper suppresses repeated logs of identical errors, and logs summaries of them periodically instead.
+type deduper struct {
+	mu        sync.Mutex
+	entries   map[string]*dedupEntry
+	afterFunc func(d time.Duration, f func())
+}
+
+// newDeduper creates a deduper scheduling summaries with afterFunc, which is replaced on tests.
+func newDeduper(afterFunc func(d time.Duration, f func())) *deduper {
+	return &deduper{entries: map[string]*dedupEntry{}, afterFunc: afterFunc}
+}
+
+type dedupEntry struct {
+	err        error
+	window     time.Duration
+	suppressed int
+	meta       LogMeta
+	opts       Options
+}
+
+// dedupSummary is logged instead of errors suppressed by deduper.
+type dedupSummary struct {
+	err    error
+	count  int
+	window time.Duration
+}
+
+func (s *dedupSummary) Error() string {
+	summary := strings.SplitN(s.err.Error(), "\n", 2)[0]
+	return fmt.Sprintf("%s (seen %d more times in last %s)", summary, s.count, s.window)
+}
+
+func (s *dedupSummary) Unwrap() error {
+	return s.err
+}
+
+func (d *deduper) log(err error, meta LogMeta, opts Options) {
+	key := dedupKey(err, opts)
+
+	d.mu.Lock()
+	if e, ok := d.entries[key]; ok {
+		e.suppressed++
+		e.meta = meta
+		e.opts = opts
+		d.mu.Unlock()
+		return
+	}
+	d.entries[key] = &dedupEntry{
+		err:    err,
+		window: opts.LogDedupWindow,
+		meta:   meta,
+		opts:   opts,
+	}
+	d.mu.Unlock()
+
+	d.afterFunc(opts.LogDedupWindow, func() { d.flush(key) })
+	writeLog(err, meta, opts)
+}
+
+// dedupKey identifies errors deduplicated together: ones from the same site with the same reason,
+// logged to the same logger. Numbers and addresses in messages are ignored as on PanicError.Fingerprint.
+//
+// Loggers are identified by their pointers, or by their code if they are functions since functions are not
+// comparable. So closures of the same function (e.g. two loggers given to LogWithLeveledLogger) share errors.
+func dedupKey(err error, opts Options) string {
+	reason := strings.SplitN(err.Error(), "\n", 2)[0]
+	var logger string
+	if opts.ErrorLogger != nil {
+		logger = fmt.Sprintf("%T %p", opts.ErrorLogger, opts.ErrorLogger)
+	} else {
+		logger = fmt.Sprintf("%p", opts.Logger)
+	}
+	return strings.Join([]string{Fingerprint(err), normalizeReason(reason), logger}, "|")
+}
+
+// flush logs a summary of errors suppressed in the last window.
+// If nothing has been suppressed, the error is forgotten so it would be logged in full next time.
+func (d *deduper) flush(key string) {
+	d.mu.Lock()
+	e := d.entries[key]
+	if e.suppressed == 0 {
+		delete(d.entries, key)
+		d.mu.Unlock()
+		return
+	}
+	summary := &dedupSummary{err: e.err, count: e.suppressed, window: e.window}
+	meta, opts := e.meta, e.opts
+	e.suppressed = 0
+	d.mu.Unlock()
+
+	d.afterFunc(e.window, func() { d.flush(key) })
+	writeLog(summary, meta, opts)
+}
@@ -0,0 +1,128 @@
+package errorist
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	pkgErrors "github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDedupLogs(t *testing.T) {
+	Convey("Logging errors with a deduper", t, func() {
+		timers := &fakeTimers{}
+		d := newDeduper(timers.AfterFunc)
+		logger := &loggerMock{}
+		window := 50 * time.Millisecond
+		opts := DefaultOptions
+		WithLogHandler(logger.Log)(&opts)
+		DedupLogs(window)(&opts)
+
+		Convey("It should log the first occurrence only, and then a summary", func() {
+			for i := 0; i < 5; i++ {
+				d.log(pkgErrors.New("dedup test"), LogMeta{}, opts)
+			}
+			So(logger.Logs(), ShouldResemble, []string{"dedup test"})
+
+			timers.Fire()
+			So(logger.Logs(), ShouldHaveLength, 2)
+			So(logger.Logs()[1], ShouldEqual, "dedup test (seen 4 more times in last 50ms)")
+
+			Convey("It should log in full again after a quiet window", func() {
+				timers.Fire()
+				d.log(pkgErrors.New("dedup test"), LogMeta{}, opts)
+				So(logger.Logs(), ShouldHaveLength, 3)
+				So(logger.Logs()[2], ShouldEqual, "dedup test")
+			})
+		})
+
+		Convey("It should not deduplicate errors from different sites", func() {
+			d.log(errWithPkgErrorsNew(), LogMeta{}, opts)
+			d.log(errWithPkgErrorsWrap(), LogMeta{}, opts)
+			So(logger.Logs(), ShouldHaveLength, 2)
+		})
+
+		Convey("It should deduplicate recovered panics", func() {
+			for i := 0; i < 3; i++ {
+				d.log(panicByKind("map"), LogMeta{}, opts)
+			}
+			So(logger.Logs(), ShouldHaveLength, 1)
+			So(logger.Logs()[0], ShouldStartWith, "panic: assignment to entry in nil map")
+
+			timers.Fire()
+			So(logger.Logs(), ShouldHaveLength, 2)
+			So(logger.Logs()[1], ShouldEqual, "panic: assignment to entry in nil map (seen 2 more times in last 50ms)")
+		})
+
+		Convey("It should not deduplicate different panics from the same function", func() {
+			d.log(panicByKind("map"), LogMeta{}, opts)
+			d.log(panicByKind("index"), LogMeta{}, opts)
+			So(logger.Logs(), ShouldHaveLength, 2)
+			So(logger.Logs()[1], ShouldStartWith, "panic: runtime error: index out of range")
+		})
+
+		Convey("It should not deduplicate errors logged to different loggers", func() {
+			other := &errorLoggerMock{}
+			otherOpts := opts
+			WithErrorLogger(other)(&otherOpts)
+
+			d.log(pkgErrors.New("dedup test"), LogMeta{}, opts)
+			d.log(pkgErrors.New("dedup test"), LogMeta{}, otherOpts)
+			So(logger.Logs(), ShouldHaveLength, 1)
+			So(other.Entries(), ShouldHaveLength, 1)
+		})
+	})
+
+	Convey("Logging errors with DedupLogs", t, func() {
+		logger := &errorLoggerMock{}
+
+		Convey("It should deduplicate them", func() {
+			for i := 0; i < 3; i++ {
+				CloseWithLogOnErr(&closerMock{ReturnError: pkgErrors.New("dedup option test")},
+					WithErrorLogger(logger), DedupLogs(time.Hour))
+			}
+			So(logger.Entries(), ShouldHaveLength, 1)
+		})
+	})
+}
+
+// fakeTimers records functions scheduled by deduper, to be fired manually.
+type fakeTimers struct {
+	mu    sync.Mutex
+	funcs []func()
+}
+
+func (t *fakeTimers) AfterFunc(_ time.Duration, f func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.funcs = append(t.funcs, f)
+}
+
+// Fire runs functions scheduled so far, as if their windows have passed.
+func (t *fakeTimers) Fire() {
+	t.mu.Lock()
+	funcs := t.funcs
+	t.funcs = nil
+	t.mu.Unlock()
+	for _, f := range funcs {
+		f()
+	}
+}
+
+type loggerMock struct {
+	mu   sync.Mutex
+	logs []string
+}
+
+func (m *loggerMock) Log(err string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs = append(m.logs, err)
+}
+
+func (m *loggerMock) Logs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.logs...)
+}
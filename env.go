@@ -0,0 +1,117 @@
+package errorist
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Environment variables read by LoadOptionsFromEnv.
+const (
+	EnvDetailedTrace       = "ERRORIST_DETAILED_TRACE"
+	EnvSkipNonProjectFiles = "ERRORIST_SKIP_NON_PROJECT_FILES"
+	EnvIncludedPackages    = "ERRORIST_INCLUDED_PACKAGES"
+	EnvMaxFrames           = "ERRORIST_MAX_FRAMES"
+	EnvSkipFrames          = "ERRORIST_SKIP_FRAMES"
+	EnvFormat              = "ERRORIST_FORMAT"
+	EnvLogDedupWindow      = "ERRORIST_LOG_DEDUP_WINDOW"
+)
+
+// LoadOptionsFromEnv reads options from environment variables and adds them to global options,
+// so they override options set by SetGlobalOptions before. Unset or empty variables are ignored.
+//
+//	ERRORIST_DETAILED_TRACE          boolean, e.g. "true"
+//	ERRORIST_SKIP_NON_PROJECT_FILES  boolean, e.g. "false"
+//	ERRORIST_INCLUDED_PACKAGES       comma-separated package names, e.g. "github.com/my/app,github.com/my/lib"
+//	ERRORIST_MAX_FRAMES              non-negative integer, 0 for unlimited
+//	ERRORIST_SKIP_FRAMES             non-negative integer
+//	ERRORIST_FORMAT                  name of a registered Formatter, e.g. "logfmt"
+//	ERRORIST_LOG_DEDUP_WINDOW        duration, e.g. "1m"
+//
+// If any of variables is invalid, it returns an error describing all of them and global options are left unchanged.
+func LoadOptionsFromEnv() error {
+	opts, err := OptionsFromEnv()
+	if err != nil {
+		return err
+	}
+	updateRegistry(func(r *registry) {
+		r.global = append(copyOptions(r.global), opts...)
+	})
+	return nil
+}
+
+// OptionsFromEnv reads options from environment variables without applying them.
+// Please refer LoadOptionsFromEnv for the variables.
+func OptionsFromEnv() (opts []Option, err error) {
+	var problems []string
+	invalid := func(name, value, expected string) {
+		problems = append(problems, fmt.Sprintf("%s=%q must be %s", name, value, expected))
+	}
+
+	if v, ok := lookupEnv(EnvDetailedTrace); ok {
+		if b, err := strconv.ParseBool(v); err != nil {
+			invalid(EnvDetailedTrace, v, "a boolean")
+		} else {
+			opts = append(opts, func(o *Options) { o.DetailedStacktrace = b })
+		}
+	}
+	if v, ok := lookupEnv(EnvSkipNonProjectFiles); ok {
+		if b, err := strconv.ParseBool(v); err != nil {
+			invalid(EnvSkipNonProjectFiles, v, "a boolean")
+		} else {
+			opts = append(opts, func(o *Options) { o.SkipNonProjectFiles = b })
+		}
+	}
+	if v, ok := lookupEnv(EnvIncludedPackages); ok {
+		var pkgs []string
+		for _, pkg := range strings.Split(v, ",") {
+			if pkg = strings.TrimSpace(pkg); pkg != "" {
+				pkgs = append(pkgs, pkg)
+			}
+		}
+		opts = append(opts, IncludedPackages(pkgs...))
+	}
+	if v, ok := lookupEnv(EnvMaxFrames); ok {
+		if n, err := strconv.Atoi(v); err != nil || n < 0 {
+			invalid(EnvMaxFrames, v, "a non-negative integer")
+		} else {
+			opts = append(opts, WithMaxFrames(n))
+		}
+	}
+	if v, ok := lookupEnv(EnvSkipFrames); ok {
+		if n, err := strconv.Atoi(v); err != nil || n < 0 {
+			invalid(EnvSkipFrames, v, "a non-negative integer")
+		} else {
+			opts = append(opts, WithSkipFrames(n))
+		}
+	}
+	if v, ok := lookupEnv(EnvFormat); ok {
+		if f, err := ParseFormat(v); err != nil {
+			invalid(EnvFormat, v, "one of "+strings.Join(FormatterNames(), ", "))
+		} else {
+			opts = append(opts, WithFormatter(f))
+		}
+	}
+	if v, ok := lookupEnv(EnvLogDedupWindow); ok {
+		if d, err := time.ParseDuration(v); err != nil || d < 0 {
+			invalid(EnvLogDedupWindow, v, `a non-negative duration (e.g. "1m")`)
+		} else {
+			opts = append(opts, DedupLogs(d))
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.Errorf("errorist: invalid environment variables: %s", strings.Join(problems, "; "))
+	}
+	return opts, nil
+}
+
+func lookupEnv(name string) (string, bool) {
+	v, ok := os.LookupEnv(name)
+	v = strings.TrimSpace(v)
+	return v, ok && v != ""
+}
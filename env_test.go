@@ -0,0 +1,69 @@
+package errorist
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLoadOptionsFromEnv(t *testing.T) {
+	defer SetGlobalOptions()
+
+	Convey("Calling errorist.LoadOptionsFromEnv", t, func() {
+		SetGlobalOptions(WithMaxFrames(10), Wrapf("global"))
+		setEnv := func(env map[string]string) {
+			for name, value := range env {
+				So(os.Setenv(name, value), ShouldBeNil)
+			}
+			Reset(func() {
+				for name := range env {
+					So(os.Unsetenv(name), ShouldBeNil)
+				}
+			})
+		}
+
+		Convey("With valid variables, it should merge them into global options", func() {
+			setEnv(map[string]string{
+				EnvDetailedTrace:       "true",
+				EnvSkipNonProjectFiles: "false",
+				EnvIncludedPackages:    "github.com/my/app, github.com/my/lib",
+				EnvMaxFrames:           "20",
+				EnvSkipFrames:          "1",
+				EnvFormat:              "logfmt",
+				EnvLogDedupWindow:      "1m",
+			})
+			So(LoadOptionsFromEnv(), ShouldBeNil)
+
+			o := EffectiveOptions()
+			So(o.DetailedStacktrace, ShouldBeTrue)
+			So(o.SkipNonProjectFiles, ShouldBeFalse)
+			So(o.IncludedPackages, ShouldResemble, []string{"github.com/my/app", "github.com/my/lib"})
+			So(o.MaxFrames, ShouldEqual, 20)
+			So(o.SkipFrames, ShouldEqual, 1)
+			So(o.Formatter, ShouldNotBeNil)
+			So(o.LogDedupWindow, ShouldEqual, time.Minute)
+			So(o.WrapArguments, ShouldResemble, []interface{}{"global"})
+		})
+
+		Convey("Without variables, it should leave global options unchanged", func() {
+			So(LoadOptionsFromEnv(), ShouldBeNil)
+			So(EffectiveOptions().MaxFrames, ShouldEqual, 10)
+		})
+
+		Convey("With invalid variables, it should describe all of them", func() {
+			setEnv(map[string]string{
+				EnvDetailedTrace: "yes please",
+				EnvMaxFrames:     "-1",
+				EnvFormat:        "xml",
+			})
+			err := LoadOptionsFromEnv()
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, `ERRORIST_DETAILED_TRACE="yes please" must be a boolean`)
+			So(err.Error(), ShouldContainSubstring, `ERRORIST_MAX_FRAMES="-1" must be a non-negative integer`)
+			So(err.Error(), ShouldContainSubstring, `ERRORIST_FORMAT="xml" must be one of `)
+			So(EffectiveOptions().MaxFrames, ShouldEqual, 10)
+		})
+	})
+}
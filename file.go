@@ -0,0 +1,58 @@
+package errorist
+
+import (
+	stdlibErrors "errors"
+	"os"
+)
+
+// CloseAndRemoveWithErrCapture is used if you want to close and remove a temporary file,
+// and fail the function or method on errors of them (make sure the `error` return argument is
+// named as `err`). The file is closed before being removed, and errors of both are joined.
+//
+// Files already closed or removed are tolerated. It does nothing if the file is nil.
+func CloseAndRemoveWithErrCapture(f *os.File, capture *error, opts ...Option) {
+	if f == nil {
+		return
+	}
+	if err := closeAndRemove(f); err != nil && *capture == nil {
+		*capture = maybeWrap(err, applyOptions(1, opts))
+	}
+}
+
+// RemoveAllWithErrCapture is used if you want to remove a temporary directory with its children,
+// and fail the function or method on an error of it (make sure the `error` return argument is
+// named as `err`). Directories already removed are tolerated.
+func RemoveAllWithErrCapture(dir string, capture *error, opts ...Option) {
+	if err := os.RemoveAll(dir); err != nil && *capture == nil {
+		*capture = maybeWrap(err, applyOptions(1, opts))
+	}
+}
+
+func closeAndRemove(f *os.File) error {
+	closeErr := f.Close()
+	if stdlibErrors.Is(closeErr, os.ErrClosed) {
+		closeErr = nil
+	}
+	removeErr := os.Remove(f.Name())
+	if stdlibErrors.Is(removeErr, os.ErrNotExist) {
+		removeErr = nil
+	}
+	return joinErrors(closeErr, removeErr)
+}
+
+// joinErrors is same as errors.Join, but returns the error as-is if only one of them is non-nil.
+func joinErrors(errs ...error) error {
+	var nonNil []error
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	}
+	return stdlibErrors.Join(nonNil...)
+}
@@ -0,0 +1,93 @@
+package errorist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pkgErrors "github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCloseAndRemoveWithErrCapture(t *testing.T) {
+	Convey("Calling errorist.CloseAndRemoveWithErrCapture", t, func() {
+		var actualErr error
+		f, err := os.CreateTemp("", "errorist-test-*")
+		So(err, ShouldBeNil)
+
+		Convey("It should close and remove the file", func() {
+			CloseAndRemoveWithErrCapture(f, &actualErr)
+			So(actualErr, ShouldBeNil)
+
+			_, err := f.Write([]byte("a"))
+			So(pkgErrors.Is(err, os.ErrClosed), ShouldBeTrue)
+			_, err = os.Stat(f.Name())
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+
+		Convey("It should tolerate files already closed or removed", func() {
+			So(f.Close(), ShouldBeNil)
+			So(os.Remove(f.Name()), ShouldBeNil)
+			CloseAndRemoveWithErrCapture(f, &actualErr)
+			So(actualErr, ShouldBeNil)
+		})
+
+		Convey("It should capture errors with Wrapf", func() {
+			makeUnremovable(f.Name())
+			CloseAndRemoveWithErrCapture(f, &actualErr, Wrapf("cleaning up"))
+			So(actualErr, ShouldNotBeNil)
+			So(actualErr.Error(), ShouldStartWith, "cleaning up: remove "+f.Name())
+		})
+
+		Convey("It should not capture error if underlying error is already present", func() {
+			makeUnremovable(f.Name())
+			actualErr = pkgErrors.New("already present")
+			CloseAndRemoveWithErrCapture(f, &actualErr)
+			So(actualErr, ShouldBeError, "already present")
+		})
+
+		Convey("It should do nothing with nil", func() {
+			So(func() { CloseAndRemoveWithErrCapture(nil, &actualErr) }, ShouldNotPanic)
+			So(actualErr, ShouldBeNil)
+		})
+
+		Reset(func() {
+			_ = f.Close()
+			_ = os.RemoveAll(f.Name())
+		})
+	})
+}
+
+func TestRemoveAllWithErrCapture(t *testing.T) {
+	Convey("Calling errorist.RemoveAllWithErrCapture", t, func() {
+		var actualErr error
+
+		Convey("It should remove the directory with its children", func() {
+			dir, err := os.MkdirTemp("", "errorist-test-*")
+			So(err, ShouldBeNil)
+			So(os.WriteFile(filepath.Join(dir, "child"), []byte("a"), 0600), ShouldBeNil)
+
+			RemoveAllWithErrCapture(dir, &actualErr)
+			So(actualErr, ShouldBeNil)
+			_, err = os.Stat(dir)
+			So(os.IsNotExist(err), ShouldBeTrue)
+
+			Convey("It should tolerate directories already removed", func() {
+				RemoveAllWithErrCapture(dir, &actualErr)
+				So(actualErr, ShouldBeNil)
+			})
+		})
+
+		Convey("It should capture errors with Wrapf", func() {
+			RemoveAllWithErrCapture("invalid\x00name", &actualErr, Wrapf("cleaning up"))
+			So(actualErr, ShouldNotBeNil)
+			So(actualErr.Error(), ShouldStartWith, "cleaning up: ")
+		})
+	})
+}
+
+// makeUnremovable replaces the file with a non-empty directory, which can't be removed by os.Remove.
+func makeUnremovable(path string) {
+	So(os.Remove(path), ShouldBeNil)
+	So(os.MkdirAll(filepath.Join(path, "child"), 0700), ShouldBeNil)
+}
@@ -0,0 +1,118 @@
+package errorist
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Fingerprint returns an identifier of the crash site, which is identical for panics raised
+// from the same calls with the same reason regardless of builds, hosts, goroutines and argument values.
+// Numbers and addresses in the reason are ignored, so "index out of range [5]" and "[6]" are identical.
+// It can be used for grouping identical panics.
+func (pe PanicError) Fingerprint() string {
+	return hashFingerprint(normalizeReason(pe.Reason), fingerprintFrames(pe.Frames, pe.Options))
+}
+
+var reasonNumbers = regexp.MustCompile(`0x[0-9a-fA-F]+|[0-9]+`)
+
+// normalizeReason replaces numbers and addresses in the panic reason, which vary by values.
+func normalizeReason(reason string) string {
+	return reasonNumbers.ReplaceAllString(reason, "N")
+}
+
+// Fingerprint returns an identifier of the place where the error has been created,
+// using the stacktrace of an error created or wrapped by `github.com/pkg/errors` package.
+// For *PanicError, it is same as PanicError.Fingerprint.
+//
+// Errors without stacktraces are identified by their types and messages.
+func Fingerprint(err error, opts ...Option) string {
+	if err == nil {
+		return ""
+	}
+	var pe *PanicError
+	if errors.As(err, &pe) {
+		return pe.Fingerprint()
+	}
+	if tr := innermostStackTracer(err); tr != nil {
+		return fingerprintFrames(stackTracerFrames(tr), applyOptions(1, opts))
+	}
+	return hashFingerprint(fmt.Sprintf("%T", err), err.Error())
+}
+
+func fingerprintFrames(frames []Frame, opts Options) string {
+	goPaths := getGOPATHs()
+
+	var all, project []string
+	for _, frame := range frames {
+		if frame.Elided != "" {
+			continue
+		}
+		call := frame.Function + " " + filepath.Base(frame.File)
+		if opts.FingerprintWithLines {
+			call += fmt.Sprintf(":%d", frame.Line)
+		}
+		all = append(all, call)
+		if isProjectFrame(frame, goPaths, opts) {
+			project = append(project, call)
+		}
+	}
+	if len(project) == 0 {
+		return hashFingerprint(all...)
+	}
+	return hashFingerprint(project...)
+}
+
+// isProjectFrame returns whether the frame belongs to the project,
+// which is decided by IncludedPackages if set, or by GOPATH otherwise.
+func isProjectFrame(frame Frame, goPaths []string, opts Options) bool {
+	if strings.HasPrefix(frame.Function, "runtime.") || strings.HasPrefix(frame.Function, "testing.") {
+		return false
+	}
+	if len(opts.IncludedPackages) > 0 {
+		return hasIncludedPackage(frame.Function, opts.IncludedPackages)
+	}
+	return !isNonProjectFile(goPaths, frame.File)
+}
+
+func hashFingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// innermostStackTracer returns the stackTracer closest to the origin of the error.
+func innermostStackTracer(err error) (tr stackTracer) {
+	for err != nil {
+		if t, ok := err.(stackTracer); ok {
+			tr = t
+		}
+		switch e := err.(type) {
+		case interface{ Cause() error }:
+			err = e.Cause()
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			err = nil
+		}
+	}
+	return tr
+}
+
+func stackTracerFrames(tr stackTracer) (frames []Frame) {
+	for _, f := range tr.StackTrace() {
+		pc := uintptr(f) - 1
+		fn := runtime.FuncForPC(pc)
+		if fn == nil {
+			continue
+		}
+		file, line := fn.FileLine(pc)
+		frames = append(frames, Frame{Function: fn.Name(), File: file, Line: line})
+	}
+	return frames
+}
@@ -0,0 +1,97 @@
+package errorist
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFingerprint(t *testing.T) {
+	Convey("Fingerprinting a PanicError", t, func() {
+		Convey("It should be identical for panics from the same site", func() {
+			var errs []*PanicError
+			for i := 0; i < 2; i++ {
+				errs = append(errs, panicInGoroutine(i))
+			}
+			So(errs[0].GoroutineID, ShouldNotEqual, errs[1].GoroutineID)
+			So(errs[0].Fingerprint(), ShouldNotBeEmpty)
+			So(errs[0].Fingerprint(), ShouldEqual, errs[1].Fingerprint())
+		})
+
+		Convey("It should differ for panics from different sites", func() {
+			So(panicWithValue(1).Fingerprint(), ShouldNotEqual, panicAtOtherSite().Fingerprint())
+		})
+
+		Convey("It should differ for different panics from the same function", func() {
+			So(panicByKind("map").Fingerprint(), ShouldNotEqual, panicByKind("index").Fingerprint())
+			So(panicByKind("idx").Fingerprint(), ShouldEqual, panicByKind("index").Fingerprint())
+		})
+
+		Convey("It should distinguish line numbers only with FingerprintWithLines", func() {
+			a := PanicError{Frames: []Frame{{Function: "app.Func", File: "/a/app.go", Line: 1}}}
+			b := PanicError{Frames: []Frame{{Function: "app.Func", File: "/b/app.go", Line: 2}}}
+			So(a.Fingerprint(), ShouldEqual, b.Fingerprint())
+
+			optionsWith := func(opt Option) Options {
+				o := DefaultOptions
+				opt(&o)
+				return o
+			}
+			a.Options = optionsWith(FingerprintWithLines())
+			b.Options = a.Options
+			So(a.Fingerprint(), ShouldNotEqual, b.Fingerprint())
+		})
+
+		Convey("It should be returned by errorist.Fingerprint", func() {
+			pe := panicWithValue(1)
+			So(Fingerprint(pe), ShouldEqual, pe.Fingerprint())
+			So(Fingerprint(errors.Wrap(pe, "wrapped")), ShouldEqual, pe.Fingerprint())
+		})
+	})
+
+	Convey("Fingerprinting an error from pkg/errors", t, func() {
+		Convey("It should be identical for errors created on the same site", func() {
+			So(Fingerprint(errWithPkgErrorsNew()), ShouldEqual, Fingerprint(errWithPkgErrorsNew()))
+			So(Fingerprint(errWithPkgErrorsNew()), ShouldNotEqual, Fingerprint(errWithPkgErrorsWrap()))
+		})
+
+		Convey("It should use the innermost stacktrace of wrapped errors", func() {
+			So(Fingerprint(errors.Wrap(errWithPkgErrorsNew(), "wrapped")), ShouldEqual, Fingerprint(errWithPkgErrorsNew()))
+		})
+
+		Convey("Without stacktrace, it should use its type and message", func() {
+			So(Fingerprint(fmt.Errorf("a")), ShouldEqual, Fingerprint(fmt.Errorf("a")))
+			So(Fingerprint(fmt.Errorf("a")), ShouldNotEqual, Fingerprint(fmt.Errorf("b")))
+			So(Fingerprint(nil), ShouldBeEmpty)
+		})
+	})
+}
+
+func panicWithValue(v int) (pe *PanicError) {
+	defer func() { pe = WrapPanic(recover()) }()
+	panic(fmt.Sprintf("value %d", v))
+}
+
+func panicInGoroutine(v int) *PanicError {
+	errChan := make(chan *PanicError)
+	go func() { errChan <- panicWithValue(v) }()
+	return <-errChan
+}
+
+func panicAtOtherSite() (pe *PanicError) {
+	defer func() { pe = WrapPanic(recover()) }()
+	panic("other")
+}
+
+func panicByKind(kind string) (pe *PanicError) {
+	defer func() { pe = WrapPanic(recover()) }()
+	if kind == "map" {
+		var m map[string]int
+		m[kind] = 1
+	}
+	var s []int
+	_ = s[len(kind)]
+	return nil
+}
@@ -0,0 +1,197 @@
+package errorist
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Trace is a stacktrace of a goroutine which is passed to Formatter.
+type Trace struct {
+	// Reason is a description of the error, such as "panic: assignment to entry in nil map".
+	Reason string
+
+	// GoroutineID is an ID of the goroutine where the trace is taken. 0 if unknown.
+	GoroutineID int
+
+	// Frames are function calls on the trace, starting from the innermost one.
+	Frames []Frame
+}
+
+// Formatter renders a Trace into a human or machine-readable text.
+type Formatter interface {
+	Format(t Trace) string
+}
+
+// FormatterFunc is an adapter allowing ordinary functions to be used as a Formatter.
+type FormatterFunc func(t Trace) string
+
+// Format calls f(t).
+func (f FormatterFunc) Format(t Trace) string {
+	return f(t)
+}
+
+var (
+	// PrettyFormatter renders a trace as an indented list of functions and source lines.
+	// It is used by default.
+	//
+	//   panic: assignment to entry in nil map
+	//       github.com/some/app.Func (app.go:12)
+	PrettyFormatter Formatter = FormatterFunc(formatPretty)
+
+	// GoFormatter renders a trace in the layout of Go runtime panics,
+	// so it can be read again with tools like panicparse.
+	//
+	//   panic: assignment to entry in nil map
+	//
+	//   goroutine 1 [running]:
+	//   github.com/some/app.Func(...)
+	//   	/home/me/app/app.go:12
+	GoFormatter Formatter = FormatterFunc(formatGo)
+
+	// JavaFormatter renders a trace in the layout of Java exceptions.
+	//
+	//   panic: assignment to entry in nil map
+	//   	at github.com/some/app.Func(app.go:12)
+	JavaFormatter Formatter = FormatterFunc(formatJava)
+
+	// PythonFormatter renders a trace in the layout of Python tracebacks,
+	// with the most recent call last.
+	//
+	//   Traceback (most recent call last):
+	//     File "/home/me/app/app.go", line 12, in github.com/some/app.Func
+	//   panic: assignment to entry in nil map
+	PythonFormatter Formatter = FormatterFunc(formatPython)
+
+	// LogfmtFormatter renders a trace into a single line of logfmt.
+	//
+	//   reason="panic: assignment to entry in nil map" goroutine=1 trace="github.com/some/app.Func(app.go:12)"
+	LogfmtFormatter Formatter = FormatterFunc(formatLogfmt)
+)
+
+var (
+	formattersMu sync.RWMutex
+	formatters   = map[string]Formatter{
+		"pretty": PrettyFormatter,
+		"go":     GoFormatter,
+		"java":   JavaFormatter,
+		"python": PythonFormatter,
+		"logfmt": LogfmtFormatter,
+	}
+)
+
+// RegisterFormatter registers a Formatter with given name, so it can be selected with WithFormat.
+// Registering with an existing name replaces the former one.
+func RegisterFormatter(name string, f Formatter) {
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+	formatters[name] = f
+}
+
+// FormatterByName returns a Formatter registered with given name.
+func FormatterByName(name string) (f Formatter, ok bool) {
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+	f, ok = formatters[name]
+	return
+}
+
+// ParseFormat returns a Formatter registered with given name,
+// or an error describing registered names if there's no such one.
+func ParseFormat(name string) (Formatter, error) {
+	if f, ok := FormatterByName(name); ok {
+		return f, nil
+	}
+	return nil, errors.Errorf("errorist: unknown format %q, must be one of %s", name, strings.Join(FormatterNames(), ", "))
+}
+
+// FormatterNames returns sorted names of registered formatters.
+func FormatterNames() (names []string) {
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func formatPretty(t Trace) string {
+	lines := []string{t.Reason}
+	for _, frame := range t.Frames {
+		lines = append(lines, strings.Repeat(" ", 4)+frame.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatGo(t Trace) string {
+	id := t.GoroutineID
+	if id == 0 {
+		id = 1
+	}
+	lines := []string{t.Reason, "", fmt.Sprintf("goroutine %d [running]:", id)}
+	for _, frame := range t.Frames {
+		if frame.Elided != "" {
+			// the only placeholder understood by the Go runtime and panicparse
+			lines = append(lines, "...additional frames elided...")
+			continue
+		}
+		lines = append(lines, frame.Function+"(...)", fmt.Sprintf("\t%s:%d", frame.File, frame.Line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatJava(t Trace) string {
+	lines := []string{t.Reason}
+	for _, frame := range t.Frames {
+		if frame.Elided != "" {
+			lines = append(lines, "\t"+frame.Elided)
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("\tat %s(%s:%d)", frame.Function, filepath.Base(frame.File), frame.Line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatPython(t Trace) string {
+	lines := []string{"Traceback (most recent call last):"}
+	for i := len(t.Frames) - 1; i >= 0; i-- {
+		frame := t.Frames[i]
+		if frame.Elided != "" {
+			lines = append(lines, "  "+frame.Elided)
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  File %q, line %d, in %s", frame.File, frame.Line, frame.Function))
+	}
+	lines = append(lines, t.Reason)
+	return strings.Join(lines, "\n")
+}
+
+func formatLogfmt(t Trace) string {
+	var calls []string
+	for _, frame := range t.Frames {
+		if frame.Elided != "" {
+			calls = append(calls, frame.Elided)
+			continue
+		}
+		calls = append(calls, fmt.Sprintf("%s(%s:%d)", frame.Function, filepath.Base(frame.File), frame.Line))
+	}
+	pairs := []string{"reason=" + logfmtValue(t.Reason)}
+	if t.GoroutineID != 0 {
+		pairs = append(pairs, "goroutine="+strconv.Itoa(t.GoroutineID))
+	}
+	pairs = append(pairs, "trace="+logfmtValue(strings.Join(calls, " ")))
+	return strings.Join(pairs, " ")
+}
+
+func logfmtValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " =\"\\\t\r\n") {
+		return strconv.Quote(v)
+	}
+	return v
+}
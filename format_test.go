@@ -0,0 +1,103 @@
+package errorist
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/maruel/panicparse/stack"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFormatters(t *testing.T) {
+	trace := Trace{
+		Reason:      "panic: assignment to entry in nil map",
+		GoroutineID: 7,
+		Frames: []Frame{
+			{Function: "github.com/some/app.inner", File: "/home/me/app/app.go", Line: 12},
+			{Function: "github.com/some/app.Outer", File: "/home/me/app/app.go", Line: 34},
+		},
+	}
+
+	Convey("Formatting a trace", t, func() {
+		Convey("With PrettyFormatter, it should render indented frames", func() {
+			So(PrettyFormatter.Format(trace), ShouldEqual, strings.Join([]string{
+				"panic: assignment to entry in nil map",
+				"    github.com/some/app.inner (app.go:12)",
+				"    github.com/some/app.Outer (app.go:34)",
+			}, "\n"))
+		})
+
+		Convey("With GoFormatter, it should be readable by panicparse", func() {
+			out := GoFormatter.Format(trace)
+			So(out, ShouldStartWith, "panic: assignment to entry in nil map\n\ngoroutine 7 [running]:\n")
+
+			c, err := stack.ParseDump(bytes.NewBufferString(out), ioutil.Discard, false)
+			So(err, ShouldBeNil)
+			So(c, ShouldNotBeNil)
+			So(c.Goroutines, ShouldHaveLength, 1)
+			So(c.Goroutines[0].ID, ShouldEqual, 7)
+
+			calls := c.Goroutines[0].Stack.Calls
+			So(calls, ShouldHaveLength, 2)
+			So(calls[0].Func.Raw, ShouldEqual, "github.com/some/app.inner")
+			So(calls[0].SrcPath, ShouldEqual, "/home/me/app/app.go")
+			So(calls[0].Line, ShouldEqual, 12)
+		})
+
+		Convey("With JavaFormatter, it should render frames prefixed by 'at'", func() {
+			So(JavaFormatter.Format(trace), ShouldEqual, strings.Join([]string{
+				"panic: assignment to entry in nil map",
+				"\tat github.com/some/app.inner(app.go:12)",
+				"\tat github.com/some/app.Outer(app.go:34)",
+			}, "\n"))
+		})
+
+		Convey("With PythonFormatter, it should render the most recent call last", func() {
+			So(PythonFormatter.Format(trace), ShouldEqual, strings.Join([]string{
+				"Traceback (most recent call last):",
+				`  File "/home/me/app/app.go", line 34, in github.com/some/app.Outer`,
+				`  File "/home/me/app/app.go", line 12, in github.com/some/app.inner`,
+				"panic: assignment to entry in nil map",
+			}, "\n"))
+		})
+
+		Convey("With LogfmtFormatter, it should render a single line", func() {
+			So(LogfmtFormatter.Format(trace), ShouldEqual,
+				`reason="panic: assignment to entry in nil map" goroutine=7 `+
+					`trace="github.com/some/app.inner(app.go:12) github.com/some/app.Outer(app.go:34)"`)
+		})
+	})
+
+	Convey("Registering a formatter", t, func() {
+		RegisterFormatter("reason-only", FormatterFunc(func(t Trace) string { return t.Reason }))
+
+		Convey("It should be selectable by its name", func() {
+			So(FormatterNames(), ShouldContain, "reason-only")
+
+			o := DefaultOptions
+			WithFormat("reason-only")(&o)
+			So(o.Formatter, ShouldNotBeNil)
+			So(o.Formatter.Format(trace), ShouldEqual, trace.Reason)
+		})
+
+		Convey("It should report unknown names", func() {
+			_, err := ParseFormat("unknown")
+			So(err, ShouldBeError, `errorist: unknown format "unknown", must be one of `+strings.Join(FormatterNames(), ", "))
+			So(func() { WithFormat("unknown") }, ShouldPanic)
+		})
+
+		Convey("It should be used on PanicError", func() {
+			var pe *PanicError
+			func() {
+				defer func() { pe = WrapPanic(recover(), WithFormat("java")) }()
+				panicStation()
+			}()
+			So(pe, ShouldNotBeNil)
+			So(pe.GoroutineID, ShouldBeGreaterThan, 0)
+			So(pe.Frames[0].Function, ShouldNotStartWith, "runtime.gopanic")
+			So(pe.Error(), ShouldContainSubstring, "\tat github.com/therne/errorist.panicStation(panic_test.go:")
+		})
+	})
+}
@@ -0,0 +1,193 @@
+package errorist
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// maxCapturedFrames limits the depth of a captured stack, so an infinite recursion can't exhaust memory.
+	maxCapturedFrames = 1 << 20
+
+	// maxRepeatPeriod is the longest cycle of frames detected as a recursion.
+	maxRepeatPeriod = 16
+
+	// minCollapsedRepeats is the minimum number of repetitions to collapse frames.
+	minCollapsedRepeats = 3
+)
+
+// Frame is a single function call on a stacktrace.
+type Frame struct {
+	// Function is a fully-qualified function name (e.g. "github.com/some/app.Func").
+	Function string
+
+	// File is an absolute path of the source file.
+	File string
+
+	// Line is a line number in the source file.
+	Line int
+
+	// Elided is only set on a placeholder standing for frames omitted from the trace,
+	// describing what has been omitted (e.g. "… repeated 412 more times …").
+	Elided string
+}
+
+// String returns a function name with a source line, such as "github.com/some/app.Func (app.go:12)".
+func (f Frame) String() string {
+	if f.Elided != "" {
+		return f.Elided
+	}
+	return fmt.Sprintf("%s (%s:%d)", f.Function, filepath.Base(f.File), f.Line)
+}
+
+func (f Frame) sameCall(other Frame) bool {
+	return f.Function == other.Function && f.File == other.File && f.Line == other.Line
+}
+
+// callerFrames returns frames of the calling goroutine, starting from the caller of callerFrames.
+// Frames are filtered by IncludedPackages or SkipNonProjectFiles.
+func callerFrames(skip int, opts Options) []Frame {
+	return filterFrames(captureFrames(skip+1), opts)
+}
+
+// captureFrames returns all frames of the calling goroutine, starting from the caller of captureFrames.
+func captureFrames(skip int) (frames []Frame) {
+	pc := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(2+skip, pc)
+		if n < len(pc) || len(pc) >= maxCapturedFrames {
+			pc = pc[:n]
+			break
+		}
+		pc = make([]uintptr, 2*len(pc))
+	}
+	if len(pc) == 0 {
+		return nil
+	}
+	callers := runtime.CallersFrames(pc)
+	for {
+		frame, more := callers.Next()
+		if !more {
+			break
+		}
+		frames = append(frames, Frame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+		})
+	}
+	return frames
+}
+
+// filterFrames removes frames excluded from stacktraces by IncludedPackages or SkipNonProjectFiles.
+func filterFrames(frames []Frame, opts Options) (filtered []Frame) {
+	goPaths := getGOPATHs()
+	for _, frame := range frames {
+		if !isSkippedFrame(frame, goPaths, opts) {
+			filtered = append(filtered, frame)
+		}
+	}
+	return filtered
+}
+
+// trimPanicFrames removes frames of deferred calls and runtime.gopanic,
+// so the trace starts from where the panic has been raised.
+func trimPanicFrames(frames []Frame) []Frame {
+	for i, frame := range frames {
+		if strings.HasPrefix(frame.Function, "runtime.gopanic") {
+			return frames[i+1:]
+		}
+	}
+	return frames
+}
+
+// panicFrames returns frames of the panicking goroutine, starting from where the panic has been raised.
+// Outside of a panic, frames start from the caller of panicFrames, skipping given number of
+// additional frames in addition to CallerSkip.
+func panicFrames(skip int, opts Options) []Frame {
+	frames := captureFrames(1)
+	if trimmed := trimPanicFrames(frames); len(trimmed) < len(frames) {
+		frames = trimmed
+	} else {
+		frames = dropFrames(frames, skip+opts.CallerSkip)
+	}
+	frames = filterFrames(frames, opts)
+	return compactFrames(dropFrames(frames, opts.SkipFrames), opts.MaxFrames)
+}
+
+func dropFrames(frames []Frame, n int) []Frame {
+	if n <= 0 {
+		return frames
+	}
+	if n > len(frames) {
+		n = len(frames)
+	}
+	return frames[n:]
+}
+
+// compactFrames collapses recursive frames and elides the middle of the trace
+// if it still has more than limit frames, so both the innermost frames and
+// the origin of the goroutine are kept.
+func compactFrames(frames []Frame, limit int) []Frame {
+	frames = collapseRepeatedFrames(frames)
+	if limit <= 0 || len(frames) <= limit {
+		return frames
+	}
+	head := (limit + 1) / 2
+	tail := limit - head
+
+	compacted := make([]Frame, 0, limit+1)
+	compacted = append(compacted, frames[:head]...)
+	compacted = append(compacted, Frame{
+		Elided: fmt.Sprintf("… %d frames elided …", len(frames)-head-tail),
+	})
+	return append(compacted, frames[len(frames)-tail:]...)
+}
+
+// collapseRepeatedFrames replaces consecutive repetitions of a frame or a cycle of frames
+// with a single occurrence followed by a placeholder.
+func collapseRepeatedFrames(frames []Frame) (collapsed []Frame) {
+	for i := 0; i < len(frames); {
+		period, repeats := findRepetition(frames, i)
+		if repeats < minCollapsedRepeats {
+			collapsed = append(collapsed, frames[i])
+			i++
+			continue
+		}
+		collapsed = append(collapsed, frames[i:i+period]...)
+		collapsed = append(collapsed, Frame{
+			Elided: fmt.Sprintf("… repeated %d more times …", repeats-1),
+		})
+		i += period * repeats
+	}
+	return collapsed
+}
+
+// findRepetition finds the shortest cycle of frames starting at the given index
+// which repeats at least minCollapsedRepeats times.
+func findRepetition(frames []Frame, start int) (period, repeats int) {
+	for period = 1; period <= maxRepeatPeriod; period++ {
+		repeats = 1
+		for end := start + (repeats+1)*period; end <= len(frames); end += period {
+			if !sameCalls(frames[start:start+period], frames[end-period:end]) {
+				break
+			}
+			repeats++
+		}
+		if repeats >= minCollapsedRepeats {
+			return period, repeats
+		}
+	}
+	return 1, 1
+}
+
+func sameCalls(a, b []Frame) bool {
+	for i := range a {
+		if !a[i].sameCall(b[i]) {
+			return false
+		}
+	}
+	return true
+}
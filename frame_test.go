@@ -0,0 +1,118 @@
+package errorist
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCompactFrames(t *testing.T) {
+	Convey("Compacting frames", t, func() {
+		Convey("It should collapse consecutive repeated frames", func() {
+			frames := []Frame{testFrame("top", 1)}
+			for i := 0; i < 10; i++ {
+				frames = append(frames, testFrame("recurse", 2))
+			}
+			frames = append(frames, testFrame("main", 3))
+
+			compacted := compactFrames(frames, 100)
+			So(compacted, ShouldHaveLength, 4)
+			So(compacted[0].Function, ShouldEqual, "top")
+			So(compacted[1].Function, ShouldEqual, "recurse")
+			So(compacted[2].Elided, ShouldEqual, "… repeated 9 more times …")
+			So(compacted[3].Function, ShouldEqual, "main")
+		})
+
+		Convey("It should collapse cycles of frames", func() {
+			var frames []Frame
+			for i := 0; i < 5; i++ {
+				frames = append(frames, testFrame("walkA", 1), testFrame("walkB", 2))
+			}
+			frames = append(frames, testFrame("main", 3))
+
+			compacted := compactFrames(frames, 100)
+			So(compacted, ShouldHaveLength, 4)
+			So(compacted[0].Function, ShouldEqual, "walkA")
+			So(compacted[1].Function, ShouldEqual, "walkB")
+			So(compacted[2].Elided, ShouldEqual, "… repeated 4 more times …")
+			So(compacted[3].Function, ShouldEqual, "main")
+		})
+
+		Convey("It should not collapse frames repeated only twice", func() {
+			frames := []Frame{testFrame("a", 1), testFrame("a", 1), testFrame("b", 2)}
+			So(compactFrames(frames, 100), ShouldResemble, frames)
+		})
+
+		Convey("It should elide the middle of frames exceeding the limit", func() {
+			var frames []Frame
+			for i := 0; i < 20; i++ {
+				frames = append(frames, testFrame(fmt.Sprintf("fn%d", i), i))
+			}
+
+			compacted := compactFrames(frames, 5)
+			So(compacted, ShouldHaveLength, 6)
+			So(compacted[0].Function, ShouldEqual, "fn0")
+			So(compacted[2].Function, ShouldEqual, "fn2")
+			So(compacted[3].Elided, ShouldEqual, "… 15 frames elided …")
+			So(compacted[4].Function, ShouldEqual, "fn18")
+			So(compacted[5].Function, ShouldEqual, "fn19")
+		})
+	})
+
+	Convey("Recovering from a panic in deep recursion", t, func() {
+		var pe *PanicError
+		func() {
+			defer func() { pe = WrapPanic(recover()) }()
+			recursivePanic(500)
+		}()
+
+		Convey("It should keep both the panic site and the origin", func() {
+			So(pe, ShouldNotBeNil)
+			So(len(pe.Frames), ShouldBeLessThan, 20)
+			So(pe.Frames[0].Function, ShouldEndWith, "recursivePanic")
+			So(pe.Error(), ShouldContainSubstring, "repeated")
+			So(pe.Error(), ShouldContainSubstring, "TestCompactFrames")
+			So(strings.Count(pe.Error(), "recursivePanic"), ShouldBeLessThan, 5)
+		})
+	})
+}
+
+func TestIncludedPackages(t *testing.T) {
+	Convey("Recovering from a panic with IncludedPackages", t, func() {
+		var pe *PanicError
+		func() {
+			defer func() { pe = WrapPanic(recover(), IncludedPackages("github.com/therne/errorist.recursive")) }()
+			recursivePanic(1)
+		}()
+
+		Convey("It should only include frames of given packages", func() {
+			So(pe.Frames, ShouldHaveLength, 2)
+			So(pe.Frames[0].Function, ShouldEqual, "github.com/therne/errorist.recursivePanic")
+			So(pe.Frames[1].Function, ShouldEqual, "github.com/therne/errorist.recursivePanic")
+		})
+
+		Convey("It should be applied on detailed stacktraces", func() {
+			func() {
+				defer func() {
+					pe = WrapPanic(recover(), WithDetailedTrace(), IncludedPackages("github.com/therne/errorist.recursive"))
+				}()
+				recursivePanic(1)
+			}()
+			So(pe.Stack, ShouldHaveLength, 3)
+			So(pe.Stack[1], ShouldEndWith, "errorist.recursivePanic()")
+		})
+	})
+}
+
+func testFrame(fn string, line int) Frame {
+	return Frame{Function: fn, File: "/src/app.go", Line: line}
+}
+
+func recursivePanic(depth int) {
+	if depth == 0 {
+		panic("too deep")
+	}
+	recursivePanic(depth - 1)
+}
@@ -0,0 +1,192 @@
+package errorist
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/maruel/panicparse/stack"
+	"github.com/pkg/errors"
+)
+
+// DumpGoroutines writes stacktraces of all goroutines to w, like SIGQUIT does but more readable.
+// Goroutines with identical states and stacks are aggregated into a bucket, and stacks are filtered
+// and formatted with options as same as PanicError.
+func DumpGoroutines(w io.Writer, opts ...Option) error {
+	return dumpGoroutines(w, applyOptions(1, opts))
+}
+
+// DumpGoroutinesOnSignal dumps goroutines to w whenever one of given signals is received, until stop is called.
+// It is safe to call stop more than once.
+// If no signal is given, SIGQUIT and SIGUSR1 are used on Unix, and it does nothing on other platforms.
+//
+// Note that handling SIGQUIT prevents the Go runtime from dumping goroutines and exiting on it.
+func DumpGoroutinesOnSignal(w io.Writer, sigs []os.Signal, opts ...Option) (stop func()) {
+	opt := applyOptions(1, opts)
+	if len(sigs) == 0 {
+		sigs = defaultDumpSignals
+	}
+	if len(sigs) == 0 {
+		// signal.Notify without signals relays all of them, including os.Interrupt
+		return func() {}
+	}
+	received := make(chan os.Signal, 1)
+	stopped := make(chan struct{})
+	signal.Notify(received, sigs...)
+	go func() {
+		for {
+			select {
+			case <-received:
+				_ = dumpGoroutines(w, opt)
+			case <-stopped:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(received)
+			close(stopped)
+		})
+	}
+}
+
+// DumpGoroutinesHandler returns an http.Handler responding with a dump of goroutines.
+// It should not be exposed publicly.
+func DumpGoroutinesHandler(opts ...Option) http.Handler {
+	opt := applyOptions(1, opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		_ = dumpGoroutines(w, opt)
+	})
+}
+
+func dumpGoroutines(w io.Writer, opts Options) error {
+	c, err := stack.ParseDump(bytes.NewReader(normalizeDump(allGoroutineStacks())), io.Discard, false)
+	if err != nil {
+		return errors.Wrap(err, "errorist: parse goroutines")
+	}
+	var goroutines []*stack.Goroutine
+	if c != nil {
+		goroutines = c.Goroutines
+	}
+	if len(goroutines) > 0 {
+		// the current goroutine comes first, which shouldn't show calls dumping goroutines
+		goroutines[0].Stack.Calls = trimDumpCalls(goroutines[0].Stack.Calls)
+	}
+	buckets := stack.Aggregate(goroutines, stack.AnyValue)
+	formatter := opts.Formatter
+	if formatter == nil {
+		formatter = PrettyFormatter
+	}
+	goPaths := getGOPATHs()
+
+	var total int
+	for _, b := range buckets {
+		total += len(b.IDs)
+	}
+	if _, err := fmt.Fprintf(w, "%d goroutines in %d buckets\n", total, len(buckets)); err != nil {
+		return err
+	}
+	for _, b := range buckets {
+		var frames []Frame
+		for _, call := range b.Stack.Calls {
+			frame := Frame{Function: call.Func.Raw, File: call.SrcPath, Line: call.Line}
+			if !isSkippedFrame(frame, goPaths, opts) {
+				frames = append(frames, frame)
+			}
+		}
+		if b.Stack.Elided {
+			frames = append(frames, Frame{Elided: "… additional frames elided …"})
+		}
+		trace := Trace{
+			Reason:      bucketDescription(b),
+			GoroutineID: b.IDs[0],
+			Frames:      compactFrames(frames, opts.MaxFrames),
+		}
+		if _, err := fmt.Fprintf(w, "\n%s\n", formatter.Format(trace)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bucketDescription describes goroutines in the bucket,
+// such as "goroutine #5, #6 [chan receive, 2 minutes] created by github.com/some/app.Run (app.go:12)".
+func bucketDescription(b *stack.Bucket) string {
+	var desc string
+	if len(b.IDs) < 3 {
+		var ids []string
+		for _, id := range b.IDs {
+			ids = append(ids, fmt.Sprintf("#%d", id))
+		}
+		desc = "goroutine " + strings.Join(ids, ", ")
+	} else {
+		desc = fmt.Sprintf("%d similar goroutines", len(b.IDs))
+	}
+	state := b.State
+	if s := b.SleepString(); s != "" {
+		state += ", " + s
+	}
+	if b.Locked {
+		state += ", locked to thread"
+	}
+	desc += " [" + state + "]"
+	if b.CreatedBy.Func.Raw != "" {
+		createdBy := Frame{Function: b.CreatedBy.Func.Raw, File: b.CreatedBy.SrcPath, Line: b.CreatedBy.Line}
+		desc += " created by " + createdBy.String()
+	}
+	return desc
+}
+
+// trimDumpCalls removes calls of dumpGoroutines and its caller in errorist.
+func trimDumpCalls(calls []stack.Call) []stack.Call {
+	for i, call := range calls {
+		if strings.HasSuffix(call.Func.Raw, "errorist.dumpGoroutines") {
+			if i+2 > len(calls) {
+				return nil
+			}
+			return calls[i+2:]
+		}
+	}
+	return calls
+}
+
+func allGoroutineStacks() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// normalizeDump rewrites a dump of goroutines into the format known to panicparse, which fails on
+// arguments and creators printed by recent Go versions (e.g. "{0x85c9d8?, 0x0?}" and "in goroutine 1").
+// Arguments are not shown on dumps, so they are dropped.
+func normalizeDump(dump []byte) []byte {
+	lines := bytes.Split(dump, []byte("\n"))
+	for i, line := range lines {
+		switch {
+		case bytes.HasPrefix(line, []byte("created by ")):
+			if j := bytes.Index(line, []byte(" in goroutine ")); j >= 0 {
+				lines[i] = line[:j]
+			}
+		case len(line) > 0 && line[0] != '\t' && bytes.HasSuffix(line, []byte(")")):
+			if j := bytes.LastIndexByte(line, '('); j > 0 {
+				lines[i] = append(line[:j:j], "()"...)
+			}
+		}
+	}
+	return bytes.Join(lines, []byte("\n"))
+}
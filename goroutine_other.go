@@ -0,0 +1,8 @@
+//go:build !unix
+
+package errorist
+
+import "os"
+
+// signals for dumping goroutines are not defined on other platforms, so they must be given explicitly.
+var defaultDumpSignals []os.Signal
@@ -0,0 +1,158 @@
+package errorist
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/maruel/panicparse/stack"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const sampleGoroutineDump = `goroutine 7 [running]:
+github.com/some/app.(*Server).Handle(0xc000120000, {0x85c9d8?, 0xc00010faa0?})
+	/home/me/app/server.go:84 +0x45
+created by github.com/some/app.Run in goroutine 1
+	/home/me/app/app.go:12 +0x4d4
+
+goroutine 1 [chan receive, 2 minutes, locked to thread]:
+main.main()
+	/home/me/app/main.go:5 +0x9b
+
+goroutine 9 [chan receive, 3 minutes]:
+github.com/some/app.worker(...)
+	/home/me/app/worker.go:20
+...additional frames elided...
+created by github.com/some/app.Run in goroutine 1
+	/home/me/app/app.go:15 +0x4d
+
+goroutine 10 [chan receive]:
+github.com/some/app.worker(...)
+	/home/me/app/worker.go:20
+...additional frames elided...
+created by github.com/some/app.Run in goroutine 1
+	/home/me/app/app.go:15 +0x4d
+`
+
+func TestNormalizeDump(t *testing.T) {
+	Convey("Parsing a normalized dump of goroutines with panicparse", t, func() {
+		c, err := stack.ParseDump(bytes.NewReader(normalizeDump([]byte(sampleGoroutineDump))), io.Discard, false)
+		So(err, ShouldBeNil)
+		So(c, ShouldNotBeNil)
+		So(c.Goroutines, ShouldHaveLength, 4)
+
+		Convey("It should parse states, frames and creators", func() {
+			g := c.Goroutines[0]
+			So(g.ID, ShouldEqual, 7)
+			So(g.State, ShouldEqual, "running")
+			So(g.Stack.Calls, ShouldHaveLength, 1)
+			So(g.Stack.Calls[0].Func.Raw, ShouldEqual, "github.com/some/app.(*Server).Handle")
+			So(g.Stack.Calls[0].SrcPath, ShouldEqual, "/home/me/app/server.go")
+			So(g.Stack.Calls[0].Line, ShouldEqual, 84)
+			So(g.CreatedBy.Func.Raw, ShouldEqual, "github.com/some/app.Run")
+			So(g.CreatedBy.Line, ShouldEqual, 12)
+
+			So(c.Goroutines[1].State, ShouldEqual, "chan receive")
+			So(c.Goroutines[1].Locked, ShouldBeTrue)
+			So(c.Goroutines[2].Stack.Elided, ShouldBeTrue)
+		})
+
+		Convey("It should aggregate goroutines with identical stacks", func() {
+			buckets := stack.Aggregate(c.Goroutines, stack.AnyValue)
+			So(buckets, ShouldHaveLength, 3)
+
+			var descriptions []string
+			for _, b := range buckets {
+				descriptions = append(descriptions, bucketDescription(b))
+			}
+			So(descriptions, ShouldContain,
+				"goroutine #9, #10 [chan receive, 0~3 minutes] created by github.com/some/app.Run (app.go:15)")
+		})
+	})
+}
+
+func TestDumpGoroutines(t *testing.T) {
+	Convey("Calling errorist.DumpGoroutines", t, func() {
+		release := make(chan struct{})
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go blockedGoroutine(release, &wg)
+		}
+		Reset(func() {
+			close(release)
+			wg.Wait()
+		})
+		// wait for goroutines to be blocked
+		time.Sleep(10 * time.Millisecond)
+
+		Convey("It should dump all goroutines aggregated into buckets", func() {
+			buf := &bytes.Buffer{}
+			So(DumpGoroutines(buf), ShouldBeNil)
+
+			dump := buf.String()
+			So(dump, ShouldContainSubstring, "goroutines in")
+			So(dump, ShouldContainSubstring, "3 similar goroutines [chan receive] created by github.com/therne/errorist.TestDumpGoroutines")
+			So(dump, ShouldContainSubstring, "    github.com/therne/errorist.blockedGoroutine (goroutine_test.go:")
+			So(dump, ShouldContainSubstring, "goroutine #")
+			So(dump, ShouldNotContainSubstring, "errorist.dumpGoroutines")
+		})
+
+		Convey("It should be formatted with options", func() {
+			buf := &bytes.Buffer{}
+			So(DumpGoroutines(buf, WithFormat("java")), ShouldBeNil)
+			So(buf.String(), ShouldContainSubstring, "\tat github.com/therne/errorist.blockedGoroutine(goroutine_test.go:")
+		})
+
+		Convey("It should only include frames of IncludedPackages", func() {
+			buf := &bytes.Buffer{}
+			So(DumpGoroutines(buf, IncludedPackages("github.com/therne/errorist.blocked")), ShouldBeNil)
+			So(buf.String(), ShouldContainSubstring, "github.com/therne/errorist.blockedGoroutine (goroutine_test.go:")
+			So(buf.String(), ShouldNotContainSubstring, "testing.tRunner")
+		})
+
+		Convey("It should be served by DumpGoroutinesHandler", func() {
+			w := httptest.NewRecorder()
+			DumpGoroutinesHandler().ServeHTTP(w, httptest.NewRequest("GET", "/debug/goroutines", nil))
+			So(w.Code, ShouldEqual, 200)
+			So(w.Header().Get("Content-Type"), ShouldStartWith, "text/plain")
+			So(w.Body.String(), ShouldContainSubstring, "3 similar goroutines")
+		})
+
+		Convey("It should be triggered by signals with DumpGoroutinesOnSignal", func() {
+			w := &chanWriter{written: make(chan string, 1)}
+			stop := DumpGoroutinesOnSignal(w, []os.Signal{syscall.SIGHUP})
+			defer stop()
+
+			signalSelf(syscall.SIGHUP)
+			select {
+			case dump := <-w.written:
+				So(dump, ShouldContainSubstring, "goroutines in")
+			case <-time.After(5 * time.Second):
+				So("dumped", ShouldEqual, "timed out")
+			}
+			So(stop, ShouldNotPanic)
+		})
+	})
+}
+
+func blockedGoroutine(release chan struct{}, wg *sync.WaitGroup) {
+	defer wg.Done()
+	<-release
+}
+
+// chanWriter sends the first write to the channel.
+type chanWriter struct {
+	once    sync.Once
+	written chan string
+}
+
+func (w *chanWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { w.written <- string(p) })
+	return len(p), nil
+}
@@ -0,0 +1,10 @@
+//go:build unix
+
+package errorist
+
+import (
+	"os"
+	"syscall"
+)
+
+var defaultDumpSignals = []os.Signal{syscall.SIGQUIT, syscall.SIGUSR1}
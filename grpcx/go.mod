@@ -0,0 +1,26 @@
+module github.com/therne/errorist/grpcx
+
+go 1.21
+
+require (
+	github.com/smartystreets/goconvey v1.6.4
+	github.com/therne/errorist v0.0.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240123012728-ef4313101c80
+	google.golang.org/grpc v1.62.1
+)
+
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/gopherjs/gopherjs v0.0.0-20181017120253-0766667cb4d1 // indirect
+	github.com/jtolds/gls v4.20.0+incompatible // indirect
+	github.com/maruel/panicparse v1.5.0 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/smartystreets/assertions v0.0.0-20180927180507-b2de0cb4f26d // indirect
+	golang.org/x/net v0.20.0 // indirect
+	golang.org/x/sys v0.16.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/protobuf v1.32.0 // indirect
+)
+
+// resolved from the parent directory until errorist is tagged
+replace github.com/therne/errorist => ../
@@ -0,0 +1,36 @@
+package grpcx
+
+import "github.com/therne/errorist"
+
+type Options struct {
+	// ErrorOptions are errorist options used for wrapping and logging panics.
+	ErrorOptions []errorist.Option
+
+	// Debug exposes reasons and stacktraces of panics on statuses, as errdetails.DebugInfo.
+	// It should not be enabled in production.
+	Debug bool
+}
+
+type Option func(o *Options)
+
+// WithErrorOptions sets errorist options used for wrapping and logging panics.
+func WithErrorOptions(opts ...errorist.Option) Option {
+	return func(o *Options) {
+		o.ErrorOptions = append(o.ErrorOptions, opts...)
+	}
+}
+
+// WithDebug exposes reasons and stacktraces of panics on statuses, as errdetails.DebugInfo.
+// It should not be enabled in production.
+func WithDebug(enabled bool) Option {
+	return func(o *Options) {
+		o.Debug = enabled
+	}
+}
+
+func applyOptions(opts []Option) (o Options) {
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
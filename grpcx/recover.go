@@ -0,0 +1,119 @@
+// Package grpcx provides gRPC interceptors recovering from panics with errorist.
+//
+// Panics are converted into statuses with codes.Internal, hiding their details from peers unless WithDebug is set.
+package grpcx
+
+import (
+	"context"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/therne/errorist"
+)
+
+// UnaryServerInterceptor returns a server interceptor recovering from panics of unary handlers.
+// The panic is wrapped into *errorist.PanicError and logged with the logger on errorist options,
+// and then returned as a status with codes.Internal.
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	options := applyOptions(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = panicStatus(errorist.WrapPanicCtx(ctx, r, options.ErrorOptions...), options)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor recovering from panics of stream handlers.
+// Please refer UnaryServerInterceptor for details.
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	options := applyOptions(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = panicStatus(errorist.WrapPanicCtx(ss.Context(), r, options.ErrorOptions...), options)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// UnaryClientInterceptor returns a client interceptor recovering from panics on calls,
+// such as ones raised from interceptors chained after it or codecs.
+// Please refer UnaryServerInterceptor for details.
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	options := applyOptions(opts)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = panicStatus(errorist.WrapPanicCtx(ctx, r, options.ErrorOptions...), options)
+			}
+		}()
+		return invoker(ctx, method, req, reply, cc, callOpts...)
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor recovering from panics on streams,
+// including ones raised while sending or receiving messages.
+// Please refer UnaryServerInterceptor for details.
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	options := applyOptions(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (cs grpc.ClientStream, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = panicStatus(errorist.WrapPanicCtx(ctx, r, options.ErrorOptions...), options)
+			}
+		}()
+		cs, err = streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			return nil, err
+		}
+		return &recoveringClientStream{ClientStream: cs, options: options}, nil
+	}
+}
+
+// recoveringClientStream recovers from panics while sending or receiving messages.
+type recoveringClientStream struct {
+	grpc.ClientStream
+	options Options
+}
+
+func (s *recoveringClientStream) SendMsg(m interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicStatus(errorist.WrapPanicCtx(s.Context(), r, s.options.ErrorOptions...), s.options)
+		}
+	}()
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *recoveringClientStream) RecvMsg(m interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicStatus(errorist.WrapPanicCtx(s.Context(), r, s.options.ErrorOptions...), s.options)
+		}
+	}()
+	return s.ClientStream.RecvMsg(m)
+}
+
+// panicStatus logs the panic and returns a status error of it.
+func panicStatus(pe *errorist.PanicError, opts Options) error {
+	errorist.LogPanic(pe)
+	if !opts.Debug {
+		return status.Error(codes.Internal, "internal error")
+	}
+	st := status.New(codes.Internal, pe.Reason)
+	debugInfo := &errdetails.DebugInfo{Detail: pe.Reason}
+	for _, frame := range pe.Frames {
+		debugInfo.StackEntries = append(debugInfo.StackEntries, frame.String())
+	}
+	if withDetails, err := st.WithDetails(debugInfo); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
@@ -0,0 +1,224 @@
+package grpcx
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/therne/errorist"
+)
+
+func TestServerInterceptors(t *testing.T) {
+	Convey("Serving with grpcx server interceptors", t, func() {
+		logger := &loggerMock{}
+		opts := []Option{WithErrorOptions(errorist.WithLogHandler(logger.Log))}
+
+		Convey("It should recover from panics of unary handlers", func() {
+			client := dialPanickingServer(opts)
+			_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+			st := status.Convert(err)
+			So(st.Code(), ShouldEqual, codes.Internal)
+			So(st.Message(), ShouldEqual, "internal error")
+			So(st.Details(), ShouldBeEmpty)
+
+			So(logger.Logs(), ShouldHaveLength, 1)
+			So(logger.Logs()[0], ShouldStartWith, "panic: assignment to entry in nil map")
+			So(logger.Logs()[0], ShouldContainSubstring, "panicStation")
+		})
+
+		Convey("It should recover from panics of stream handlers", func() {
+			client := dialPanickingServer(opts)
+			stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+			So(err, ShouldBeNil)
+			_, err = stream.Recv()
+
+			So(status.Code(err), ShouldEqual, codes.Internal)
+			So(logger.Logs(), ShouldHaveLength, 1)
+		})
+
+		Convey("With WithDebug, it should attach the trace as a debug detail", func() {
+			client := dialPanickingServer(append(opts, WithDebug(true)))
+			_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+			st := status.Convert(err)
+			So(st.Code(), ShouldEqual, codes.Internal)
+			So(st.Message(), ShouldEqual, "panic: assignment to entry in nil map")
+			So(st.Details(), ShouldHaveLength, 1)
+
+			debugInfo := st.Details()[0].(*errdetails.DebugInfo)
+			So(debugInfo.Detail, ShouldEqual, "panic: assignment to entry in nil map")
+			So(debugInfo.StackEntries, ShouldNotBeEmpty)
+			So(strings.Join(debugInfo.StackEntries, "\n"), ShouldContainSubstring, "panicStation")
+		})
+	})
+}
+
+func TestClientInterceptors(t *testing.T) {
+	Convey("Calling with grpcx client interceptors", t, func() {
+		logger := &loggerMock{}
+		opts := []Option{WithErrorOptions(errorist.WithLogHandler(logger.Log))}
+
+		panicking := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, grpc.UnaryInvoker, ...grpc.CallOption) error {
+			panicStation()
+			return nil
+		}
+		panickingStream := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, grpc.Streamer, ...grpc.CallOption) (grpc.ClientStream, error) {
+			panicStation()
+			return nil, nil
+		}
+		client := dialPanickingServer(nil,
+			grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(opts...), panicking),
+			grpc.WithChainStreamInterceptor(StreamClientInterceptor(opts...), panickingStream),
+		)
+
+		Convey("It should recover from panics on unary calls", func() {
+			_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+			So(status.Code(err), ShouldEqual, codes.Internal)
+			So(logger.Logs(), ShouldHaveLength, 1)
+			So(logger.Logs()[0], ShouldStartWith, "panic: assignment to entry in nil map")
+		})
+
+		Convey("It should recover from panics on streams", func() {
+			_, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+			So(status.Code(err), ShouldEqual, codes.Internal)
+			So(logger.Logs(), ShouldHaveLength, 1)
+		})
+	})
+}
+
+func TestClientStreamInterceptor(t *testing.T) {
+	Convey("Streaming with grpcx client stream interceptor", t, func() {
+		logger := &loggerMock{}
+		opts := []Option{WithErrorOptions(errorist.WithLogHandler(logger.Log))}
+
+		Convey("It should recover from panics while sending messages", func() {
+			client := dialPanickingServer(nil, grpc.WithChainStreamInterceptor(
+				StreamClientInterceptor(append(opts, WithDebug(true))...),
+				panickingClientStreamInterceptor(true, false),
+			))
+			_, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+			st := status.Convert(err)
+			So(st.Code(), ShouldEqual, codes.Internal)
+			So(st.Message(), ShouldEqual, "panic: assignment to entry in nil map")
+			So(logger.Logs(), ShouldHaveLength, 1)
+			So(logger.Logs()[0], ShouldContainSubstring, "SendMsg")
+		})
+
+		Convey("It should recover from panics while receiving messages", func() {
+			client := dialPanickingServer(nil, grpc.WithChainStreamInterceptor(
+				StreamClientInterceptor(opts...),
+				panickingClientStreamInterceptor(false, true),
+			))
+			stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+			So(err, ShouldBeNil)
+			_, err = stream.Recv()
+
+			st := status.Convert(err)
+			So(st.Code(), ShouldEqual, codes.Internal)
+			So(st.Message(), ShouldEqual, "internal error")
+			So(logger.Logs(), ShouldHaveLength, 1)
+			So(logger.Logs()[0], ShouldContainSubstring, "RecvMsg")
+		})
+	})
+}
+
+// panickingClientStreamInterceptor returns an interceptor wrapping streams to panic on sending or receiving messages.
+func panickingClientStreamInterceptor(onSend, onRecv bool) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &panickingClientStream{ClientStream: cs, onSend: onSend, onRecv: onRecv}, nil
+	}
+}
+
+type panickingClientStream struct {
+	grpc.ClientStream
+	onSend, onRecv bool
+}
+
+func (s *panickingClientStream) SendMsg(m interface{}) error {
+	if s.onSend {
+		panicStation()
+	}
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *panickingClientStream) RecvMsg(m interface{}) error {
+	if s.onRecv {
+		panicStation()
+	}
+	return s.ClientStream.RecvMsg(m)
+}
+
+// dialPanickingServer starts an in-process server whose handlers panic, and returns a client of it.
+func dialPanickingServer(serverOpts []Option, dialOpts ...grpc.DialOption) grpc_health_v1.HealthClient {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(serverOpts...)),
+		grpc.StreamInterceptor(StreamServerInterceptor(serverOpts...)),
+	)
+	grpc_health_v1.RegisterHealthServer(server, &panickingHealthServer{})
+	go func() { _ = server.Serve(lis) }()
+	Reset(server.Stop)
+
+	dialer := func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		append(dialOpts, grpc.WithContextDialer(dialer), grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+	So(err, ShouldBeNil)
+	Reset(func() { _ = conn.Close() })
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+type panickingHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (s *panickingHealthServer) Check(context.Context, *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	panicStation()
+	return nil, nil
+}
+
+func (s *panickingHealthServer) Watch(*grpc_health_v1.HealthCheckRequest, grpc_health_v1.Health_WatchServer) error {
+	panicStation()
+	return nil
+}
+
+// noinspection ALL
+func panicStation() {
+	var empty map[string]string
+	empty["a"] = "b"
+}
+
+type loggerMock struct {
+	mu   sync.Mutex
+	logs []string
+}
+
+func (m *loggerMock) Log(err string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs = append(m.logs, err)
+}
+
+func (m *loggerMock) Logs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.logs...)
+}
@@ -0,0 +1,106 @@
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/therne/errorist"
+)
+
+// Problem is an error response body defined in RFC 7807 (Problem Details for HTTP APIs).
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Stacktrace is an extension member exposed only in debug mode.
+	Stacktrace []string `json:"stacktrace,omitempty"`
+}
+
+// StatusCoder can be implemented by errors to be responded with their own status codes.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// WriteError responds the error as an RFC 7807 problem (application/problem+json).
+// Please refer StatusCode for how status codes are decided.
+//
+// Messages of 5xx errors are internal, so they are redacted unless debug mode is enabled
+// by WithDebug. Stacktraces of errors are also exposed only in debug mode.
+//
+// A nil error is responded as 500 Internal Server Error without details, since it's a bug of the caller.
+func WriteError(w http.ResponseWriter, r *http.Request, err error, opts ...Option) {
+	options := applyOptions(opts)
+	status := http.StatusInternalServerError
+	if err != nil {
+		status = StatusCode(err, opts...)
+	}
+
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+	if err != nil {
+		problem.Detail = strings.SplitN(err.Error(), "\n", 2)[0]
+	}
+	if r != nil {
+		problem.Instance = r.URL.Path
+	}
+	if options.Debug {
+		for _, frame := range errorist.ErrorFrames(err) {
+			problem.Stacktrace = append(problem.Stacktrace, frame.String())
+		}
+	} else if status >= 500 {
+		problem.Detail = ""
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+// StatusCode returns an HTTP status code of the error. It is decided by, in order:
+//
+//   - StatusFunc on options
+//   - StatusCoder implemented by the error or its causes
+//   - Well-known errors: fs.ErrNotExist (404), fs.ErrPermission (403),
+//     *http.MaxBytesError (413) and context.DeadlineExceeded (504)
+//
+// Otherwise, it is 500 Internal Server Error. Codes out of 100-999 are ignored, since http.ResponseWriter
+// can't write them.
+func StatusCode(err error, opts ...Option) int {
+	if f := applyOptions(opts).StatusFunc; f != nil {
+		if status := f(err); isValidStatus(status) {
+			return status
+		}
+	}
+	var coder StatusCoder
+	if errors.As(err, &coder) && isValidStatus(coder.StatusCode()) {
+		return coder.StatusCode()
+	}
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return http.StatusNotFound
+	case errors.Is(err, fs.ErrPermission):
+		return http.StatusForbidden
+	case errors.As(err, &maxBytesErr):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+func isValidStatus(status int) bool {
+	return status >= 100 && status <= 999
+}
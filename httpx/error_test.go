@@ -0,0 +1,131 @@
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWriteError(t *testing.T) {
+	Convey("Calling httpx.WriteError", t, func() {
+		write := func(err error, opts ...Option) (*httptest.ResponseRecorder, Problem) {
+			w := httptest.NewRecorder()
+			WriteError(w, httptest.NewRequest("GET", "/items/1", nil), err, opts...)
+
+			var problem Problem
+			So(json.Unmarshal(w.Body.Bytes(), &problem), ShouldBeNil)
+			return w, problem
+		}
+
+		Convey("It should respond a problem+json body", func() {
+			w, problem := write(statusError{status: http.StatusConflict, msg: "item exists"})
+			So(w.Code, ShouldEqual, http.StatusConflict)
+			So(w.Header().Get("Content-Type"), ShouldEqual, "application/problem+json")
+			So(problem, ShouldResemble, Problem{
+				Type:     "about:blank",
+				Title:    "Conflict",
+				Status:   http.StatusConflict,
+				Detail:   "item exists",
+				Instance: "/items/1",
+			})
+		})
+
+		Convey("It should redact messages of 5xx errors", func() {
+			w, problem := write(errors.New("db password is wrong"))
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(problem.Title, ShouldEqual, "Internal Server Error")
+			So(problem.Detail, ShouldBeEmpty)
+			So(problem.Stacktrace, ShouldBeEmpty)
+		})
+
+		Convey("With WithDebug, it should expose messages and stacktraces", func() {
+			_, problem := write(errors.Wrap(errors.New("db password is wrong"), "querying"), WithDebug(true))
+			So(problem.Detail, ShouldEqual, "querying: db password is wrong")
+			So(problem.Stacktrace, ShouldNotBeEmpty)
+			So(problem.Stacktrace[0], ShouldContainSubstring, "TestWriteError")
+
+			_, problem = write(fmt.Errorf("no trace"), WithDebug(true))
+			So(problem.Stacktrace, ShouldBeEmpty)
+		})
+
+		Convey("It should respond 500 on nil errors", func() {
+			w, problem := write(nil, WithDebug(true))
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(problem, ShouldResemble, Problem{
+				Type:     "about:blank",
+				Title:    "Internal Server Error",
+				Status:   http.StatusInternalServerError,
+				Instance: "/items/1",
+			})
+		})
+
+		Convey("It should respond 500 on invalid status codes", func() {
+			So(func() { write(statusError{status: 0, msg: "no status"}) }, ShouldNotPanic)
+			w, problem := write(statusError{status: 0, msg: "no status"})
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(problem.Status, ShouldEqual, http.StatusInternalServerError)
+		})
+
+		Convey("It should respond only the first line of messages", func() {
+			_, problem := write(statusError{status: http.StatusBadRequest, msg: "invalid\ndetails"})
+			So(problem.Detail, ShouldEqual, "invalid")
+		})
+	})
+}
+
+func TestStatusCode(t *testing.T) {
+	Convey("Calling httpx.StatusCode", t, func() {
+		Convey("It should use StatusCoder of the error or its causes", func() {
+			err := statusError{status: http.StatusTeapot}
+			So(StatusCode(err), ShouldEqual, http.StatusTeapot)
+			So(StatusCode(errors.Wrap(err, "wrapped")), ShouldEqual, http.StatusTeapot)
+			So(StatusCode(fmt.Errorf("wrapped: %w", err)), ShouldEqual, http.StatusTeapot)
+		})
+
+		Convey("It should ignore invalid codes of StatusCoder", func() {
+			So(StatusCode(statusError{status: 0}), ShouldEqual, http.StatusInternalServerError)
+			So(StatusCode(statusError{status: 1000}), ShouldEqual, http.StatusInternalServerError)
+			So(StatusCode(errors.Wrap(statusError{status: -1}, "wrapped")), ShouldEqual, http.StatusInternalServerError)
+		})
+
+		Convey("It should map well-known errors", func() {
+			_, err := os.Open("/does/not/exist")
+			So(StatusCode(err), ShouldEqual, http.StatusNotFound)
+			So(StatusCode(os.ErrPermission), ShouldEqual, http.StatusForbidden)
+			So(StatusCode(&http.MaxBytesError{Limit: 1}), ShouldEqual, http.StatusRequestEntityTooLarge)
+			So(StatusCode(context.DeadlineExceeded), ShouldEqual, http.StatusGatewayTimeout)
+			So(StatusCode(errors.New("unknown")), ShouldEqual, http.StatusInternalServerError)
+		})
+
+		Convey("With WithStatusFunc, it should be used first", func() {
+			opt := WithStatusFunc(func(err error) int {
+				if err == context.Canceled {
+					return http.StatusServiceUnavailable
+				}
+				return 0
+			})
+			So(StatusCode(context.Canceled, opt), ShouldEqual, http.StatusServiceUnavailable)
+			So(StatusCode(statusError{status: http.StatusTeapot}, opt), ShouldEqual, http.StatusTeapot)
+		})
+	})
+}
+
+type statusError struct {
+	status int
+	msg    string
+}
+
+func (e statusError) Error() string {
+	return e.msg
+}
+
+func (e statusError) StatusCode() int {
+	return e.status
+}
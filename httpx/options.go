@@ -0,0 +1,68 @@
+package httpx
+
+import "github.com/therne/errorist"
+
+// ResponseFormat is a format of error responses.
+type ResponseFormat int
+
+const (
+	// ResponseText responds errors in plain text.
+	ResponseText ResponseFormat = iota
+
+	// ResponseJSON responds errors in JSON.
+	ResponseJSON
+)
+
+type Options struct {
+	// ErrorOptions are errorist options used for wrapping and logging errors.
+	ErrorOptions []errorist.Option
+
+	// Response is the format of error responses. Defaults to ResponseText.
+	Response ResponseFormat
+
+	// Debug exposes stacktraces and messages of internal errors on responses.
+	// It should not be enabled in production.
+	Debug bool
+
+	// StatusFunc decides status codes of errors prior to the default rules of StatusCode.
+	// It returns 0 to fall back to the default rules.
+	StatusFunc func(err error) int
+}
+
+type Option func(o *Options)
+
+// WithErrorOptions sets errorist options used for wrapping and logging errors.
+func WithErrorOptions(opts ...errorist.Option) Option {
+	return func(o *Options) {
+		o.ErrorOptions = append(o.ErrorOptions, opts...)
+	}
+}
+
+// RespondWith sets the format of error responses.
+func RespondWith(format ResponseFormat) Option {
+	return func(o *Options) {
+		o.Response = format
+	}
+}
+
+// WithDebug exposes stacktraces and messages of internal errors on responses.
+// It should not be enabled in production.
+func WithDebug(enabled bool) Option {
+	return func(o *Options) {
+		o.Debug = enabled
+	}
+}
+
+// WithStatusFunc sets a function deciding status codes of errors. Please refer Options.StatusFunc.
+func WithStatusFunc(f func(err error) int) Option {
+	return func(o *Options) {
+		o.StatusFunc = f
+	}
+}
+
+func applyOptions(opts []Option) (o Options) {
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
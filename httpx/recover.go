@@ -0,0 +1,112 @@
+// Package httpx provides net/http integrations of errorist.
+package httpx
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/therne/errorist"
+)
+
+// Recover returns a handler recovering from panics of the given handler.
+// The panic is wrapped into *errorist.PanicError and logged with the logger on errorist options,
+// and then responded with 500 Internal Server Error.
+//
+// Panics with http.ErrAbortHandler are propagated as-is, so the server aborts the response silently.
+// If the response has been already written, the panic is logged and the response is aborted
+// with http.ErrAbortHandler, since the status can't be changed anymore.
+func Recover(handler http.Handler, opts ...Option) http.Handler {
+	options := applyOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseWriter{ResponseWriter: w}
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+			pe := errorist.WrapPanicCtx(r.Context(), recovered, options.ErrorOptions...)
+			errorist.LogPanic(pe)
+			if rw.written {
+				panic(http.ErrAbortHandler)
+			}
+			writePanic(w, pe, options)
+		}()
+		handler.ServeHTTP(rw, r)
+	})
+}
+
+type panicResponse struct {
+	Error      string   `json:"error"`
+	Stacktrace []string `json:"stacktrace,omitempty"`
+}
+
+func writePanic(w http.ResponseWriter, pe *errorist.PanicError, opts Options) {
+	status := http.StatusInternalServerError
+	resp := panicResponse{Error: http.StatusText(status)}
+	if opts.Debug {
+		resp.Error = pe.Reason
+		for _, frame := range pe.Frames {
+			resp.Stacktrace = append(resp.Stacktrace, frame.String())
+		}
+	}
+
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if opts.Response == ResponseJSON {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(resp)
+		return
+	}
+	body := resp.Error
+	if opts.Debug {
+		body = pe.Pretty()
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(body + "\n"))
+}
+
+// responseWriter records whether the response has been written.
+type responseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	// informational (1xx) responses can be followed by the final response
+	if status >= 200 {
+		w.written = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.written = true
+		f.Flush()
+	}
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	w.written = true
+	return h.Hijack()
+}
+
+// Unwrap returns the original http.ResponseWriter, which is used by http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
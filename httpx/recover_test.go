@@ -0,0 +1,124 @@
+package httpx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/therne/errorist"
+)
+
+func TestRecover(t *testing.T) {
+	Convey("Serving with httpx.Recover", t, func() {
+		logger := &loggerMock{}
+		errorOpts := WithErrorOptions(errorist.WithLogHandler(logger.Log))
+		serve := func(h http.HandlerFunc, opts ...Option) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			Recover(h, append([]Option{errorOpts}, opts...)...).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+			return w
+		}
+
+		Convey("It should pass through responses without panics", func() {
+			w := serve(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			})
+			So(w.Code, ShouldEqual, http.StatusTeapot)
+			So(logger.Logs(), ShouldBeEmpty)
+		})
+
+		Convey("It should respond 500 in plain text and log the panic", func() {
+			w := serve(panickingHandler)
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(w.Header().Get("Content-Type"), ShouldStartWith, "text/plain")
+			So(w.Body.String(), ShouldEqual, "Internal Server Error\n")
+
+			So(logger.Logs(), ShouldHaveLength, 1)
+			So(logger.Logs()[0], ShouldStartWith, "panic: assignment to entry in nil map")
+		})
+
+		Convey("It should respond 500 in JSON", func() {
+			w := serve(panickingHandler, RespondWith(ResponseJSON))
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(w.Header().Get("Content-Type"), ShouldStartWith, "application/json")
+
+			var resp panicResponse
+			So(json.Unmarshal(w.Body.Bytes(), &resp), ShouldBeNil)
+			So(resp.Error, ShouldEqual, "Internal Server Error")
+			So(resp.Stacktrace, ShouldBeEmpty)
+		})
+
+		Convey("With WithDebug, it should respond with the stacktrace", func() {
+			w := serve(panickingHandler, WithDebug(true))
+			So(w.Body.String(), ShouldStartWith, "panic: assignment to entry in nil map")
+			So(w.Body.String(), ShouldContainSubstring, "panickingHandler")
+
+			w = serve(panickingHandler, WithDebug(true), RespondWith(ResponseJSON))
+			var resp panicResponse
+			So(json.Unmarshal(w.Body.Bytes(), &resp), ShouldBeNil)
+			So(resp.Error, ShouldEqual, "panic: assignment to entry in nil map")
+			So(resp.Stacktrace, ShouldNotBeEmpty)
+		})
+
+		Convey("It should propagate http.ErrAbortHandler without logging", func() {
+			So(func() {
+				serve(func(w http.ResponseWriter, r *http.Request) {
+					panic(http.ErrAbortHandler)
+				})
+			}, ShouldPanicWith, http.ErrAbortHandler)
+			So(logger.Logs(), ShouldBeEmpty)
+		})
+
+		Convey("After the response has been written, it should log the panic and abort", func() {
+			var w *httptest.ResponseRecorder
+			So(func() {
+				w = httptest.NewRecorder()
+				h := func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte("partial"))
+					panickingHandler(w, r)
+				}
+				Recover(http.HandlerFunc(h), errorOpts).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+			}, ShouldPanicWith, http.ErrAbortHandler)
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Body.String(), ShouldEqual, "partial")
+			So(logger.Logs(), ShouldHaveLength, 1)
+		})
+
+		Convey("It should use errorist options carried by the request context", func() {
+			ctxLogger := &loggerMock{}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+			r = r.WithContext(errorist.WithOptions(r.Context(), errorist.WithLogHandler(ctxLogger.Log)))
+			Recover(http.HandlerFunc(panickingHandler)).ServeHTTP(w, r)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(ctxLogger.Logs(), ShouldHaveLength, 1)
+		})
+	})
+}
+
+// noinspection ALL
+func panickingHandler(w http.ResponseWriter, r *http.Request) {
+	var empty map[string]string
+	empty["a"] = "b"
+}
+
+type loggerMock struct {
+	mu   sync.Mutex
+	logs []string
+}
+
+func (m *loggerMock) Log(err string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs = append(m.logs, err)
+}
+
+func (m *loggerMock) Logs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.logs...)
+}
@@ -0,0 +1,150 @@
+package errorist
+
+import "fmt"
+
+// Operation is a kind of operations where errors are logged by errorist.
+type Operation string
+
+const (
+	OperationClose    Operation = "close"
+	OperationStop     Operation = "stop"
+	OperationRecover  Operation = "recover"
+	OperationRollback Operation = "rollback"
+)
+
+// Level is a severity of logged errors.
+type Level int
+
+const (
+	// LevelDefault lets errorist decide the level by the operation:
+	// LevelWarn on closing, and LevelError on stopping, recovering and rolling back.
+	LevelDefault Level = iota
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var defaultLevels = map[Operation]Level{
+	OperationClose:    LevelWarn,
+	OperationStop:     LevelError,
+	OperationRecover:  LevelError,
+	OperationRollback: LevelError,
+}
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "default"
+	}
+}
+
+// LogMeta is metadata on an error logged by errorist.
+type LogMeta struct {
+	// Operation is the operation caused the error.
+	Operation Operation
+
+	// Level is the severity of the error, decided by LogAt or the operation.
+	Level Level
+
+	// ResourceType is the type of the resource being closed or stopped (e.g. "*os.File").
+	// Empty on recovered panics.
+	ResourceType string
+
+	// Caller is the frame where errorist has been called.
+	// On recovered panics, it is where the panic has been raised.
+	Caller Frame
+
+	// Wrap is the context message added by Wrapf. Empty if not wrapped.
+	Wrap string
+}
+
+// ErrorLogger logs errors on functions end with "WithErrLog".
+// Unlike Options.Logger, it receives the error itself so it can inspect the error.
+type ErrorLogger interface {
+	LogError(err error, meta LogMeta)
+}
+
+// ErrorLoggerFunc is an adapter allowing ordinary functions to be used as an ErrorLogger.
+type ErrorLoggerFunc func(err error, meta LogMeta)
+
+// LogError calls f(err, meta).
+func (f ErrorLoggerFunc) LogError(err error, meta LogMeta) {
+	f(err, meta)
+}
+
+// StringLogger returns an ErrorLogger logging messages of errors with given function.
+func StringLogger(logger func(err string)) ErrorLogger {
+	return ErrorLoggerFunc(func(err error, _ LogMeta) {
+		logger(err.Error())
+	})
+}
+
+// LeveledLogger includes leveled logging functions on Logrus and zap's SugaredLogger.
+// Other loggers sharing same function signatures can be also used.
+type LeveledLogger interface {
+	Debug(args ...interface{})
+	Info(args ...interface{})
+	Warn(args ...interface{})
+	Error(args ...interface{})
+}
+
+// LeveledErrorLogger returns an ErrorLogger logging messages of errors with the function of
+// given logger matching the level of each error.
+func LeveledErrorLogger(l LeveledLogger) ErrorLogger {
+	return ErrorLoggerFunc(func(err error, meta LogMeta) {
+		switch meta.Level {
+		case LevelDebug:
+			l.Debug(err.Error())
+		case LevelInfo:
+			l.Info(err.Error())
+		case LevelWarn:
+			l.Warn(err.Error())
+		default:
+			l.Error(err.Error())
+		}
+	})
+}
+
+// newLogMeta creates LogMeta for the caller of the function calling newLogMeta.
+func newLogMeta(op Operation, resource interface{}, opts Options) LogMeta {
+	meta := LogMeta{
+		Operation: op,
+		Level:     opts.LogLevel,
+		Caller:    callerFrame(2 + opts.CallerSkip),
+		Wrap:      wrapMessage(opts),
+	}
+	if meta.Level == LevelDefault {
+		meta.Level = defaultLevels[op]
+	}
+	if resource != nil {
+		meta.ResourceType = fmt.Sprintf("%T", resource)
+	}
+	return meta
+}
+
+// logError logs the error with the logger on given options, deduplicating it if LogDedupWindow is set.
+func logError(err error, meta LogMeta, opts Options) {
+	if opts.LogDedupWindow > 0 {
+		logDedup.log(err, meta, opts)
+		return
+	}
+	writeLog(err, meta, opts)
+}
+
+// writeLog logs the error with ErrorLogger on given options, or Logger if it's not set.
+func writeLog(err error, meta LogMeta, opts Options) {
+	if opts.ErrorLogger != nil {
+		opts.ErrorLogger.LogError(err, meta)
+		return
+	}
+	opts.Logger(err.Error())
+}
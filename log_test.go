@@ -0,0 +1,135 @@
+package errorist
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	pkgErrors "github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestErrorLogger(t *testing.T) {
+	Convey("Logging errors with ErrorLogger", t, func() {
+		logger := &errorLoggerMock{}
+
+		Convey("It should receive the error and metadata of closing", func() {
+			cause := pkgErrors.New("test")
+			CloseWithLogOnErr(&closerMock{ReturnError: cause}, WithErrorLogger(logger), Wrapf("closing %d", 1))
+
+			So(logger.Entries(), ShouldHaveLength, 1)
+			entry := logger.Entries()[0]
+			So(errors.Is(entry.err, cause), ShouldBeTrue)
+			So(entry.meta.Operation, ShouldEqual, OperationClose)
+			So(entry.meta.ResourceType, ShouldEqual, "*errorist.closerMock")
+			So(entry.meta.Caller.Function, ShouldStartWith, "github.com/therne/errorist.TestErrorLogger")
+			So(entry.meta.Wrap, ShouldEqual, "closing 1")
+		})
+
+		Convey("It should receive the error and metadata of stopping", func() {
+			StopWithErrLog(&stopperMock{ReturnError: pkgErrors.New("test")}, WithErrorLogger(logger))
+
+			entry := logger.Entries()[0]
+			So(entry.meta.Operation, ShouldEqual, OperationStop)
+			So(entry.meta.ResourceType, ShouldEqual, "*errorist.stopperMock")
+			So(entry.meta.Wrap, ShouldBeEmpty)
+		})
+
+		Convey("It should receive PanicError on recovered panics", func() {
+			func() {
+				defer RecoverWithErrLog(WithErrorLogger(logger))
+				panicStation()
+			}()
+
+			entry := logger.Entries()[0]
+			var pe *PanicError
+			So(errors.As(entry.err, &pe), ShouldBeTrue)
+			So(pe.Frames, ShouldNotBeEmpty)
+			So(entry.meta.Operation, ShouldEqual, OperationRecover)
+			So(entry.meta.ResourceType, ShouldBeEmpty)
+			So(entry.meta.Caller.Function, ShouldEqual, "github.com/therne/errorist.panicStation")
+		})
+
+		Convey("It should be overridden by string-based loggers set later", func() {
+			var logged string
+			CloseWithLogOnErr(
+				&closerMock{ReturnError: pkgErrors.New("test")},
+				WithErrorLogger(logger),
+				LogWithPrintfFamily(func(format string, _ ...interface{}) { logged = format }),
+			)
+			So(logger.Entries(), ShouldBeEmpty)
+			So(logged, ShouldEqual, "test")
+		})
+
+		Convey("With StringLogger, it should log error messages", func() {
+			var logged string
+			CloseWithLogOnErr(&closerMock{ReturnError: pkgErrors.New("test")}, WithErrorLogger(StringLogger(func(err string) {
+				logged = err
+			})))
+			So(logged, ShouldEqual, "test")
+		})
+	})
+}
+
+func TestLogLevels(t *testing.T) {
+	Convey("Logging errors with LeveledLogger", t, func() {
+		logger := &leveledLoggerMock{}
+		opt := LogWithLeveledLogger(logger)
+
+		Convey("It should use default levels of each operation", func() {
+			CloseWithLogOnErr(&closerMock{ReturnError: pkgErrors.New("close")}, opt)
+			StopWithErrLog(&stopperMock{ReturnError: pkgErrors.New("stop")}, opt)
+			func() {
+				defer RecoverWithErrLog(opt)
+				panic("recover")
+			}()
+
+			So(logger.logs, ShouldHaveLength, 3)
+			So(logger.logs[0], ShouldEqual, "warn: close")
+			So(logger.logs[1], ShouldEqual, "error: stop")
+			So(logger.logs[2], ShouldStartWith, "error: panic: recover")
+		})
+
+		Convey("With LogAt, it should use the given level", func() {
+			CloseWithLogOnErr(&closerMock{ReturnError: pkgErrors.New("close")}, opt, LogAt(LevelDebug))
+			StopWithErrLog(&stopperMock{ReturnError: pkgErrors.New("stop")}, opt, LogAt(LevelInfo))
+			So(logger.logs, ShouldResemble, []string{"debug: close", "info: stop"})
+		})
+	})
+}
+
+type leveledLoggerMock struct {
+	logs []string
+}
+
+func (m *leveledLoggerMock) Debug(args ...interface{}) { m.log("debug", args) }
+func (m *leveledLoggerMock) Info(args ...interface{})  { m.log("info", args) }
+func (m *leveledLoggerMock) Warn(args ...interface{})  { m.log("warn", args) }
+func (m *leveledLoggerMock) Error(args ...interface{}) { m.log("error", args) }
+
+func (m *leveledLoggerMock) log(level string, args []interface{}) {
+	m.logs = append(m.logs, level+": "+fmt.Sprint(args...))
+}
+
+type errorLoggerEntry struct {
+	err  error
+	meta LogMeta
+}
+
+type errorLoggerMock struct {
+	mu      sync.Mutex
+	entries []errorLoggerEntry
+}
+
+func (m *errorLoggerMock) LogError(err error, meta LogMeta) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, errorLoggerEntry{err: err, meta: meta})
+}
+
+func (m *errorLoggerMock) Entries() []errorLoggerEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]errorLoggerEntry(nil), m.entries...)
+}
@@ -1,10 +1,8 @@
 package errorist
 
-import "log"
-
-var (
-	globalOptions   []Option
-	pkgLocalOptions = map[string][]Option{}
+import (
+	"log"
+	"time"
 )
 
 type Options struct {
@@ -12,11 +10,31 @@ type Options struct {
 	// It uses `log.Println` by default.
 	Logger func(err string)
 
+	// ErrorLogger specifies the logger receiving errors and their metadata on functions end with "WithErrLog".
+	// If set, it is used instead of Logger.
+	ErrorLogger ErrorLogger
+
+	// LogLevel specifies the level of errors logged on functions end with "WithErrLog".
+	// By default (LevelDefault), errors on closing are logged at LevelWarn,
+	// and errors on stopping and recovered panics are logged at LevelError.
+	// It is only effective with ErrorLogger supporting levels.
+	LogLevel Level
+
+	// LogDedupWindow specifies an interval of deduplicating logs on functions end with "WithErrLog".
+	// If set, an error is logged in full only on its first occurrence, and then the number of identical
+	// errors (sharing the same Fingerprint and message, logged to the same logger) is logged once in every interval.
+	// 0 (disabled) by default.
+	LogDedupWindow time.Duration
+
 	// DetailedStacktrace specifies verbosity of stacktrace.
 	// If it's true, running goroutine and its traces will be dumped.
 	// Otherwise, it only dumps package and function name with source lines, similar to Java's.
 	DetailedStacktrace bool
 
+	// Formatter specifies the layout of stacktraces on PanicError.
+	// If nil, PrettyFormatter is used unless DetailedStacktrace is set.
+	Formatter Formatter
+
 	// SkipNonProjectFiles specifies whether to skip stacktrace from non-project sources.
 	// true by default.
 	//
@@ -24,17 +42,51 @@ type Options struct {
 	// GOPATH from environment variable for the decision. IncludedPackages is recommended.
 	SkipNonProjectFiles bool
 
+	// MaxFrames specifies the maximum number of frames on a stacktrace. 300 by default.
+	// If a trace is deeper than the limit, frames in the middle are elided. 0 means unlimited.
+	MaxFrames int
+
+	// SkipFrames specifies the number of innermost frames skipped on a stacktrace.
+	// It can be used for hiding helper functions raising panics.
+	SkipFrames int
+
+	// CallerSkip specifies the number of additional callers skipped when errorist looks up its caller.
+	// It is for libraries wrapping errorist, so traces start at the caller of the library.
+	CallerSkip int
+
 	// IncludedPackages specifies allowed list of package names in stacktrace.
-	// If set, only packages starting with given names will be included.
+	// If set, only packages starting with given names will be included, instead of SkipNonProjectFiles.
 	IncludedPackages []string
 
+	// FingerprintWithLines specifies whether to distinguish line numbers on fingerprints. false by default.
+	// If set, fingerprints of the same crash site change whenever the source is edited.
+	FingerprintWithLines bool
+
 	// WrapArguments specifies additional context info added on error.
 	// If first argument is string, it is used to format message with rest of the arguments and
 	// will be passed to errors.Wrapf (by default) or fmt.Errorf (optional).
 	WrapArguments []interface{}
 
-	// WrapWithFmtErrorf specifies whether to use fmt.Errorf on error wrapping. false by default.
+	// WrapWithFmtErrorf specifies whether to wrap errors like fmt.Errorf("...: %w", err),
+	// without attaching a stacktrace. false by default.
 	WrapWithFmtErrorf bool
+
+	// WrapWithStack specifies whether to wrap errors with a stacktrace captured by errorist
+	// instead of pkg/errors. false by default.
+	WrapWithStack bool
+
+	// BodyDrainLimit specifies the maximum number of bytes drained from HTTP response bodies
+	// before closing them, so the connection can be reused. 64KiB by default.
+	// Bodies larger than the limit are closed without being fully drained.
+	BodyDrainLimit int64
+
+	// KillGracePeriod specifies how long a process is waited for after SIGTERM before being killed
+	// by WaitWithErrCapture. 5 seconds by default.
+	KillGracePeriod time.Duration
+
+	// ShutdownTimeout specifies how long RunUntilSignal waits for the run function to return
+	// and cleanups to finish on shutdown. 10 seconds by default.
+	ShutdownTimeout time.Duration
 }
 
 var DefaultOptions = Options{
@@ -42,6 +94,10 @@ var DefaultOptions = Options{
 
 	DetailedStacktrace:  false,
 	SkipNonProjectFiles: true,
+	MaxFrames:           300,
+	BodyDrainLimit:      64 << 10,
+	KillGracePeriod:     5 * time.Second,
+	ShutdownTimeout:     10 * time.Second,
 }
 
 type Option func(o *Options)
@@ -58,6 +114,16 @@ func Wrapf(format string, args ...interface{}) Option {
 func WrapWithFmtErrorf() Option {
 	return func(o *Options) {
 		o.WrapWithFmtErrorf = true
+		o.WrapWithStack = false
+	}
+}
+
+// WrapWithStack is an option for wrapping errors with a stacktrace captured by errorist.
+// The stacktrace starts from the caller of errorist, and can be read with Stacktrace.
+func WrapWithStack() Option {
+	return func(o *Options) {
+		o.WrapWithStack = true
+		o.WrapWithFmtErrorf = false
 	}
 }
 
@@ -68,6 +134,47 @@ func WithDetailedTrace() Option {
 	}
 }
 
+// WithFormatter is an option for rendering stacktraces with given Formatter.
+func WithFormatter(f Formatter) Option {
+	return func(o *Options) {
+		o.Formatter = f
+	}
+}
+
+// WithFormat is an option for rendering stacktraces with a Formatter registered with given name
+// (e.g. "pretty", "go", "java", "python" or "logfmt").
+// It panics on unknown names, since it's a mistake on configuration. Use ParseFormat to handle them.
+func WithFormat(name string) Option {
+	f, err := ParseFormat(name)
+	if err != nil {
+		panic(err)
+	}
+	return WithFormatter(f)
+}
+
+// WithMaxFrames is an option specifying the maximum number of frames on a stacktrace.
+// 0 means unlimited.
+func WithMaxFrames(n int) Option {
+	return func(o *Options) {
+		o.MaxFrames = n
+	}
+}
+
+// WithSkipFrames is an option for skipping given number of innermost frames on a stacktrace.
+func WithSkipFrames(n int) Option {
+	return func(o *Options) {
+		o.SkipFrames = n
+	}
+}
+
+// AddCallerSkip is an option increasing the number of callers skipped when errorist looks up its caller.
+// Libraries wrapping errorist can use it to make traces start at their caller.
+func AddCallerSkip(n int) Option {
+	return func(o *Options) {
+		o.CallerSkip += n
+	}
+}
+
 // IncludeNonProjectFiles is an option for including non-project files to stacktrace.
 // It is not recommended to use this option for production because it refers
 // GOPATH from environment variable for the decision. IncludedPackages is recommended.
@@ -85,6 +192,36 @@ func IncludedPackages(pkgs ...string) Option {
 	}
 }
 
+// FingerprintWithLines is an option for distinguishing line numbers on fingerprints.
+func FingerprintWithLines() Option {
+	return func(o *Options) {
+		o.FingerprintWithLines = true
+	}
+}
+
+// WithBodyDrainLimit is an option for setting the maximum number of bytes drained
+// from HTTP response bodies before closing them.
+func WithBodyDrainLimit(n int64) Option {
+	return func(o *Options) {
+		o.BodyDrainLimit = n
+	}
+}
+
+// WithKillGracePeriod is an option for setting how long a process is waited for
+// after SIGTERM before being killed.
+func WithKillGracePeriod(d time.Duration) Option {
+	return func(o *Options) {
+		o.KillGracePeriod = d
+	}
+}
+
+// WithShutdownTimeout is an option for setting how long RunUntilSignal waits on shutdown.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.ShutdownTimeout = d
+	}
+}
+
 // LogrusLikeLoggingFunc includes leveled logging functions on Logrus.
 // https://github.com/sirupsen/logrus#level-logging
 // Other loggers sharing same function signature can be also used.
@@ -94,6 +231,7 @@ type LogrusLikeLoggingFunc func(...interface{})
 func LogWithLogrus(lf LogrusLikeLoggingFunc) Option {
 	return func(o *Options) {
 		o.Logger = func(err string) { lf(err) }
+		o.ErrorLogger = nil
 	}
 }
 
@@ -103,6 +241,7 @@ type PrintfFamily func(string, ...interface{})
 func LogWithPrintfFamily(lf PrintfFamily) Option {
 	return func(o *Options) {
 		o.Logger = func(err string) { lf(err) }
+		o.ErrorLogger = nil
 	}
 }
 
@@ -110,26 +249,79 @@ func LogWithPrintfFamily(lf PrintfFamily) Option {
 func WithLogHandler(handler func(err string)) Option {
 	return func(o *Options) {
 		o.Logger = handler
+		o.ErrorLogger = nil
+	}
+}
+
+// DedupLogs is an option for deduplicating identical errors logged on functions end with "WithErrorLog".
+// The first occurrence is logged in full, and then a summary is logged once in every given window.
+func DedupLogs(window time.Duration) Option {
+	return func(o *Options) {
+		o.LogDedupWindow = window
+	}
+}
+
+// LogAt is an option for specifying the level of errors logged on functions end with "WithErrorLog".
+func LogAt(level Level) Option {
+	return func(o *Options) {
+		o.LogLevel = level
+	}
+}
+
+// LogWithLeveledLogger is an option for using leveled loggers like Logrus or zap's SugaredLogger
+// on functions end with "WithErrorLog". Errors are logged with the function matching their levels.
+func LogWithLeveledLogger(l LeveledLogger) Option {
+	return WithErrorLogger(LeveledErrorLogger(l))
+}
+
+// WithErrorLogger is an option for specifying ErrorLogger on functions end with "WithErrorLog".
+func WithErrorLogger(l ErrorLogger) Option {
+	return func(o *Options) {
+		o.ErrorLogger = l
 	}
 }
 
 // SetGlobalOptions sets options applied in current package scope.
 // It can override global options.
+// It is safe to be called concurrently.
 func SetGlobalOptions(opts ...Option) {
-	globalOptions = opts
+	opts = copyOptions(opts)
+	updateRegistry(func(r *registry) {
+		r.global = opts
+	})
 }
 
 // SetPackageLevelOptions sets options applied in current package scope.
-// It can override global options.
+// It can override global options. Options are also applied on nested packages
+// (e.g. options on "github.com/my/app" apply to "github.com/my/app/db") unless they override them.
+// It is safe to be called concurrently.
 func SetPackageLevelOptions(opts ...Option) {
 	pkg := callerPackageName(1)
-	pkgLocalOptions[pkg] = opts
+	opts = copyOptions(opts)
+	updateRegistry(func(r *registry) {
+		r.packages[pkg] = opts
+	})
 }
 
-func applyOptions(opts []Option) Options {
+// EffectiveOptions returns options applied on errorist functions called in current package,
+// merged in the order of global, package-level and given options. It is useful for debugging.
+func EffectiveOptions(opts ...Option) Options {
+	return applyOptions(1, opts)
+}
+
+// applyOptions merges options in the order of global, package-level and call options.
+// Package-level options are looked up from the package of the caller of applyOptions,
+// skipping given number of additional frames in addition to CallerSkip on call options.
+func applyOptions(skip int, opts []Option) Options {
+	var callOpts Options
+	for _, optFn := range opts {
+		optFn(&callOpts)
+	}
+	r := loadRegistry()
+
 	var merged []Option
-	merged = append(merged, globalOptions...)
-	merged = append(merged, pkgLocalOptions[callerPackageName(1)]...)
+	merged = append(merged, r.global...)
+	merged = append(merged, r.packageOptions(callerPackageName(1+skip+callOpts.CallerSkip))...)
 	merged = append(merged, opts...)
 
 	o := DefaultOptions
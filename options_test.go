@@ -0,0 +1,68 @@
+package errorist_test
+
+import (
+	"strings"
+	"testing"
+
+	pkgErrors "github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/therne/errorist"
+)
+
+// This test is in an external package to make sure package-level options are looked up
+// from the calling package instead of errorist itself.
+func TestSetPackageLevelOptions(t *testing.T) {
+	defer errorist.SetPackageLevelOptions()
+
+	Convey("Calling errorist.SetPackageLevelOptions", t, func() {
+		errorist.SetPackageLevelOptions(errorist.Wrapf("package"), errorist.WithMaxFrames(42))
+
+		Convey("It should be applied on calls from the package", func() {
+			var err error
+			errorist.CloseWithErrCapture(&failingCloser{}, &err)
+			So(err, ShouldBeError, "package: close failed")
+
+			errChan := make(chan error, 1)
+			errorist.StopWithErrChan(&failingStopper{}, errChan)
+			So(<-errChan, ShouldBeError, "package: stop failed")
+		})
+
+		Convey("It should be applied on recovered panics", func() {
+			var err error
+			func() {
+				defer errorist.RecoverWithErrCapture(&err)
+				panic("boom")
+			}()
+			So(err.Error(), ShouldStartWith, "package: panic: boom")
+		})
+
+		Convey("It should be applied on panics raised from other packages", func() {
+			var err error
+			func() {
+				defer errorist.RecoverWithErrCapture(&err)
+				strings.Repeat("a", -1)
+			}()
+			So(err.Error(), ShouldStartWith, "package: panic: strings: negative Repeat count")
+		})
+
+		Convey("It should be overridden by call options", func() {
+			var err error
+			errorist.CloseWithErrCapture(&failingCloser{}, &err, errorist.Wrapf("call"))
+			So(err, ShouldBeError, "call: close failed")
+		})
+
+		Convey("It should be reported by EffectiveOptions", func() {
+			So(errorist.EffectiveOptions().MaxFrames, ShouldEqual, 42)
+			So(errorist.EffectiveOptions(errorist.WithMaxFrames(1)).MaxFrames, ShouldEqual, 1)
+		})
+	})
+}
+
+type failingCloser struct{}
+
+func (failingCloser) Close() error { return pkgErrors.New("close failed") }
+
+type failingStopper struct{}
+
+func (failingStopper) Stop() error { return pkgErrors.New("stop failed") }
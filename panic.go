@@ -1,27 +1,78 @@
 package errorist
 
 import (
+	"context"
 	"fmt"
-	"math"
 	"strings"
 )
 
 func RecoverWithErrCapture(capture *error, opts ...Option) {
-	if err := WrapPanic(recover()); err != nil {
-		*capture = maybeWrap(err, applyOptions(opts))
+	if err := wrapPanic(recover(), 1, opts); err != nil {
+		*capture = maybeWrap(err, err.Options)
 	}
 }
 
 func RecoverWithErrChan(errChan chan<- error, opts ...Option) {
-	if err := WrapPanic(recover()); err != nil {
-		errChan <- maybeWrap(err, applyOptions(opts))
+	if err := wrapPanic(recover(), 1, opts); err != nil {
+		errChan <- maybeWrap(err, err.Options)
 	}
 }
 
+// RecoverWithErrLog is used if you want to recover from a panic and just log it.
+// The panic is logged with its stacktrace using the logger on options.
+func RecoverWithErrLog(opts ...Option) {
+	if err := wrapPanic(recover(), 1, opts); err != nil {
+		logError(maybeWrap(err, err.Options), newLogMeta(OperationRecover, nil, err.Options), err.Options)
+	}
+}
+
+// RecoverWithErrCaptureCtx is same as RecoverWithErrCapture, with options carried by the context.
+func RecoverWithErrCaptureCtx(ctx context.Context, capture *error, opts ...Option) {
+	if err := wrapPanic(recover(), 1, withContextOptions(ctx, opts)); err != nil {
+		*capture = maybeWrap(err, err.Options)
+	}
+}
+
+// RecoverWithErrChanCtx is same as RecoverWithErrChan, with options carried by the context.
+func RecoverWithErrChanCtx(ctx context.Context, errChan chan<- error, opts ...Option) {
+	if err := wrapPanic(recover(), 1, withContextOptions(ctx, opts)); err != nil {
+		errChan <- maybeWrap(err, err.Options)
+	}
+}
+
+// RecoverWithErrLogCtx is same as RecoverWithErrLog, with options carried by the context.
+func RecoverWithErrLogCtx(ctx context.Context, opts ...Option) {
+	if err := wrapPanic(recover(), 1, withContextOptions(ctx, opts)); err != nil {
+		logError(maybeWrap(err, err.Options), newLogMeta(OperationRecover, nil, err.Options), err.Options)
+	}
+}
+
+// LogPanic logs the panic with the logger on its options, as RecoverWithErrLog does.
+// It is useful for panics recovered and wrapped by yourself (e.g. in middlewares).
+func LogPanic(pe *PanicError) {
+	if pe == nil {
+		return
+	}
+	meta := newLogMeta(OperationRecover, nil, pe.Options)
+	for _, frame := range pe.Frames {
+		if frame.Elided == "" && packageName(frame.Function) != "runtime" {
+			meta.Caller = frame
+			break
+		}
+	}
+	logError(maybeWrap(pe, pe.Options), meta, pe.Options)
+}
+
 type PanicError struct {
 	Reason  string
 	Stack   []string
 	Options Options
+
+	// Frames are function calls of the panicking goroutine, starting from where the panic has been raised.
+	Frames []Frame
+
+	// GoroutineID is an ID of the panicking goroutine.
+	GoroutineID int
 }
 
 func (pe PanicError) Error() string {
@@ -34,17 +85,52 @@ func (pe PanicError) Pretty() (desc string) {
 			desc = fmt.Sprintf("%s\n  %s", pe.Reason, strings.Join(pe.Stack, "\n  "))
 		}
 	}()
+	if pe.Options.Formatter != nil {
+		return pe.Options.Formatter.Format(pe.Trace())
+	}
 	return fmt.Sprintf("%s\n%s", pe.Reason, formatStacktrace(pe.Stack, pe.Options))
 }
 
+// Trace returns the reason and frames of the panic, which can be rendered with a Formatter.
+func (pe PanicError) Trace() Trace {
+	return Trace{
+		Reason:      pe.Reason,
+		GoroutineID: pe.GoroutineID,
+		Frames:      pe.Frames,
+	}
+}
+
 func WrapPanic(recovered interface{}, opt ...Option) *PanicError {
+	return wrapPanic(recovered, 1, opt)
+}
+
+// WrapPanicCtx is same as WrapPanic, with options carried by the context.
+func WrapPanicCtx(ctx context.Context, recovered interface{}, opt ...Option) *PanicError {
+	return wrapPanic(recovered, 1, withContextOptions(ctx, opt))
+}
+
+// wrapPanic creates PanicError with the trace of current goroutine.
+// skip is the number of frames to skip above the caller of wrapPanic.
+func wrapPanic(recovered interface{}, skip int, opt []Option) *PanicError {
 	if recovered == nil {
 		return nil
 	}
-	opts := applyOptions(opt)
+	opts := applyOptions(skip+1, opt)
+	frames := panicFrames(skip+1, opts)
+
+	var stack []string
+	if opts.DetailedStacktrace {
+		stack = detailedStacktrace(1, opts.MaxFrames, opts)
+	} else {
+		for _, frame := range frames {
+			stack = append(stack, frame.String())
+		}
+	}
 	return &PanicError{
-		Reason:  fmt.Sprintf("panic: %s", recovered),
-		Stack:   stacktrace(0, math.MaxInt32, opts),
-		Options: opts,
+		Reason:      fmt.Sprintf("panic: %s", recovered),
+		Stack:       stack,
+		Options:     opts,
+		Frames:      frames,
+		GoroutineID: currentGoroutineID(),
 	}
 }
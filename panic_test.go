@@ -2,6 +2,7 @@ package errorist
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -46,6 +47,33 @@ func TestWrapPanicWith(t *testing.T) {
 			})
 		})
 
+		Convey("With DetailedTrace, it should start the trace from where the panic has been raised", func() {
+			var pe *PanicError
+			func() {
+				defer func() { pe = WrapPanic(recover(), WithDetailedTrace()) }()
+				panicStation()
+			}()
+			So(pe.Stack, ShouldNotBeEmpty)
+			So(pe.Stack[0], ShouldStartWith, "Goroutine #")
+			So(pe.Stack[1], ShouldContainSubstring, "errorist.panicStation()")
+
+			stack := strings.Join(pe.Stack, "\n")
+			So(stack, ShouldNotContainSubstring, "warning:")
+			So(stack, ShouldNotContainSubstring, "errorist.WrapPanic()")
+			So(stack, ShouldNotContainSubstring, "gopanic")
+		})
+
+		Convey("With DetailedTrace, it should apply SkipFrames and MaxFrames", func() {
+			var pe *PanicError
+			func() {
+				defer func() { pe = WrapPanic(recover(), WithDetailedTrace(), WithSkipFrames(1), WithMaxFrames(2)) }()
+				panicStation()
+			}()
+			So(pe.Stack, ShouldHaveLength, 4)
+			So(pe.Stack[1], ShouldNotContainSubstring, "errorist.panicStation()")
+			So(pe.Stack[2], ShouldContainSubstring, "frames elided")
+		})
+
 		Convey("With DetailedTrace skipping non-project files", func() {
 			Convey("It should catch panic correctly", func() {
 				So(func() {
@@ -66,3 +94,64 @@ func panicStation() {
 	var empty map[string]string
 	empty["a"] = "b"
 }
+
+func TestWrapPanicWithFrameOptions(t *testing.T) {
+	Convey("Calling errorist.WrapPanic", t, func() {
+		wrap := func(opts ...Option) (pe *PanicError) {
+			defer func() { pe = WrapPanic(recover(), opts...) }()
+			panicStation()
+			return nil
+		}
+
+		Convey("With WithSkipFrames, it should skip innermost frames", func() {
+			full := wrap()
+			skipped := wrap(WithSkipFrames(1))
+			So(skipped.Frames[0], ShouldResemble, full.Frames[1])
+		})
+
+		Convey("With WithMaxFrames, it should limit the number of frames", func() {
+			pe := wrap(WithMaxFrames(2))
+			So(pe.Frames, ShouldHaveLength, 3)
+			So(pe.Frames[1].Elided, ShouldNotBeEmpty)
+		})
+
+		Convey("Outside of a panic, it should start from the caller", func() {
+			pe := WrapPanic("not panicking")
+			So(pe.Frames[0].Function, ShouldStartWith, "github.com/therne/errorist.TestWrapPanicWithFrameOptions")
+
+			pe = callerSkippingHelper("not panicking")
+			So(pe.Frames[0].Function, ShouldStartWith, "github.com/therne/errorist.TestWrapPanicWithFrameOptions")
+		})
+	})
+}
+
+func callerSkippingHelper(recovered interface{}) *PanicError {
+	return WrapPanic(recovered, AddCallerSkip(1))
+}
+
+func TestLogPanic(t *testing.T) {
+	Convey("Calling errorist.LogPanic", t, func() {
+		logger := &errorLoggerMock{}
+
+		Convey("It should log the panic with the logger on its options", func() {
+			pe := func() (pe *PanicError) {
+				defer func() { pe = WrapPanic(recover(), WithErrorLogger(logger), Wrapf("handling")) }()
+				panicStation()
+				return nil
+			}()
+			LogPanic(pe)
+
+			So(logger.Entries(), ShouldHaveLength, 1)
+			entry := logger.Entries()[0]
+			So(entry.err.Error(), ShouldStartWith, "handling: panic: assignment to entry in nil map")
+			So(entry.meta.Operation, ShouldEqual, OperationRecover)
+			So(entry.meta.Level, ShouldEqual, LevelError)
+			So(entry.meta.Caller.Function, ShouldEqual, "github.com/therne/errorist.panicStation")
+		})
+
+		Convey("It should ignore nil", func() {
+			So(func() { LogPanic(nil) }, ShouldNotPanic)
+			So(logger.Entries(), ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,148 @@
+package errorist
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ExitError is an error of a process exited unsuccessfully, with the tail of its stderr if captured.
+type ExitError struct {
+	// Command is the name of the command.
+	Command string
+
+	// ExitCode is the exit code of the process, or -1 if it has been terminated by a signal.
+	ExitCode int
+
+	// Stderr is the tail of the stderr of the process. It is captured only if exec.Cmd.Stderr
+	// is a *TailWriter or *bytes.Buffer.
+	Stderr string
+
+	// Err is the original error returned by exec.Cmd.Wait.
+	Err *exec.ExitError
+}
+
+func (e *ExitError) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.Command, e.Err)
+	if e.Stderr != "" {
+		msg += "\n" + e.Stderr
+	}
+	return msg
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// WaitWithErrCapture is used if you want to wait for a started process and fail the function or method
+// if it exits unsuccessfully (make sure the `error` return argument is named as `err`).
+// An unsuccessful exit is captured as *ExitError.
+//
+// If the error is already present (i.e. on an early return), the process is terminated instead of
+// being waited for: SIGTERM is sent, and then SIGKILL after KillGracePeriod if it's still running.
+// The exit of the terminated process is joined to the present error.
+//
+// It does nothing if the process hasn't been started or has been already waited for.
+// A nil cmd is captured as an error.
+func WaitWithErrCapture(cmd *exec.Cmd, capture *error, opts ...Option) {
+	if cmd == nil {
+		*capture = joinErrors(*capture, errors.New("errorist: wait on nil cmd"))
+		return
+	}
+	if cmd.Process == nil || cmd.ProcessState != nil {
+		return
+	}
+	opt := applyOptions(1, opts)
+	if *capture != nil {
+		termErr := wrapExitError(cmd, terminate(cmd, opt.KillGracePeriod))
+		*capture = joinErrors(*capture, maybeWrap(termErr, opt))
+		return
+	}
+	if err := wrapExitError(cmd, cmd.Wait()); err != nil {
+		*capture = maybeWrap(err, opt)
+	}
+}
+
+// terminate sends SIGTERM to the process, and then SIGKILL if it doesn't exit within the grace period.
+func terminate(cmd *exec.Cmd, gracePeriod time.Duration) error {
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	// SIGTERM is not supported on some platforms (e.g. Windows), where it's killed immediately
+	if err := cmd.Process.Signal(syscall.SIGTERM); err == nil {
+		timer := time.NewTimer(gracePeriod)
+		defer timer.Stop()
+		select {
+		case err := <-done:
+			return err
+		case <-timer.C:
+		}
+	}
+	_ = cmd.Process.Kill()
+	return <-done
+}
+
+func wrapExitError(cmd *exec.Cmd, err error) error {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+	e := &ExitError{
+		Command:  filepath.Base(cmd.Path),
+		ExitCode: exitErr.ExitCode(),
+		Err:      exitErr,
+	}
+	switch stderr := cmd.Stderr.(type) {
+	case *TailWriter:
+		e.Stderr = strings.TrimSpace(stderr.String())
+	case *bytes.Buffer:
+		e.Stderr = strings.TrimSpace(string(tail(stderr.Bytes(), defaultStderrTailSize)))
+	}
+	return e
+}
+
+const defaultStderrTailSize = 4 << 10
+
+// TailWriter is an io.Writer keeping only the last bytes written, up to its size.
+// It can be used as exec.Cmd.Stderr to capture the tail of stderr on *ExitError.
+type TailWriter struct {
+	mu   sync.Mutex
+	size int
+	buf  []byte
+}
+
+// NewTailWriter creates a TailWriter keeping the last size bytes.
+func NewTailWriter(size int) *TailWriter {
+	return &TailWriter{size: size}
+}
+
+func (w *TailWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, tail(p, w.size)...)
+	if len(w.buf) > w.size {
+		w.buf = append(w.buf[:0], tail(w.buf, w.size)...)
+	}
+	return len(p), nil
+}
+
+// String returns the last bytes written.
+func (w *TailWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return string(w.buf)
+}
+
+func tail(b []byte, size int) []byte {
+	if len(b) > size {
+		return b[len(b)-size:]
+	}
+	return b
+}
@@ -0,0 +1,126 @@
+package errorist
+
+import (
+	"bufio"
+	"bytes"
+	stdlibErrors "errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	pkgErrors "github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWaitWithErrCapture(t *testing.T) {
+	Convey("Calling errorist.WaitWithErrCapture", t, func() {
+		var actualErr error
+		start := func(script string) *exec.Cmd {
+			cmd := exec.Command("sh", "-c", script)
+			cmd.Stderr = NewTailWriter(16)
+			So(cmd.Start(), ShouldBeNil)
+			return cmd
+		}
+
+		Convey("It should wait for the process", func() {
+			cmd := start("exit 0")
+			WaitWithErrCapture(cmd, &actualErr)
+			So(actualErr, ShouldBeNil)
+			So(cmd.ProcessState.Success(), ShouldBeTrue)
+		})
+
+		Convey("It should capture an unsuccessful exit with the tail of stderr", func() {
+			cmd := start("echo 'some long diagnostics' >&2; echo 'fatal: oops' >&2; exit 3")
+			WaitWithErrCapture(cmd, &actualErr, Wrapf("running %s", "job"))
+
+			var exitErr *ExitError
+			So(stdlibErrors.As(actualErr, &exitErr), ShouldBeTrue)
+			So(exitErr.Command, ShouldEqual, "sh")
+			So(exitErr.ExitCode, ShouldEqual, 3)
+			So(exitErr.Stderr, ShouldEqual, "ics\nfatal: oops")
+			So(actualErr.Error(), ShouldEqual, "running job: sh: exit status 3\nics\nfatal: oops")
+
+			var execErr *exec.ExitError
+			So(stdlibErrors.As(actualErr, &execErr), ShouldBeTrue)
+		})
+
+		Convey("It should capture the tail of stderr on bytes.Buffer", func() {
+			cmd := exec.Command("sh", "-c", "echo oops >&2; exit 1")
+			stderr := &bytes.Buffer{}
+			cmd.Stderr = stderr
+			So(cmd.Start(), ShouldBeNil)
+
+			WaitWithErrCapture(cmd, &actualErr)
+			So(actualErr.(*ExitError).Stderr, ShouldEqual, "oops")
+		})
+
+		Convey("On an early return, it should terminate the process", func() {
+			earlyErr := pkgErrors.New("early return")
+			actualErr = earlyErr
+			cmd := start("exec sleep 10")
+
+			started := time.Now()
+			WaitWithErrCapture(cmd, &actualErr)
+			So(time.Since(started), ShouldBeLessThan, 5*time.Second)
+			So(cmd.ProcessState.String(), ShouldEqual, "signal: terminated")
+			So(actualErr, ShouldBeError, "early return\nsh: signal: terminated")
+			So(stdlibErrors.Is(actualErr, earlyErr), ShouldBeTrue)
+
+			var exitErr *ExitError
+			So(stdlibErrors.As(actualErr, &exitErr), ShouldBeTrue)
+			So(exitErr.ExitCode, ShouldEqual, -1)
+		})
+
+		Convey("On an early return, it should kill the process ignoring SIGTERM after the grace period", func() {
+			actualErr = pkgErrors.New("early return")
+			cmd := exec.Command("sh", "-c", "trap '' TERM; echo ready; exec sleep 10")
+			stdout, err := cmd.StdoutPipe()
+			So(err, ShouldBeNil)
+			So(cmd.Start(), ShouldBeNil)
+
+			// wait for the trap to be set
+			_, err = bufio.NewReader(stdout).ReadString('\n')
+			So(err, ShouldBeNil)
+
+			started := time.Now()
+			WaitWithErrCapture(cmd, &actualErr, WithKillGracePeriod(100*time.Millisecond))
+			So(time.Since(started), ShouldBeGreaterThanOrEqualTo, 100*time.Millisecond)
+			So(time.Since(started), ShouldBeLessThan, 5*time.Second)
+			So(cmd.ProcessState.String(), ShouldEqual, "signal: killed")
+			So(actualErr, ShouldBeError, "early return\nsh: signal: killed")
+		})
+
+		Convey("It should capture an error with nil cmd", func() {
+			So(func() { WaitWithErrCapture(nil, &actualErr) }, ShouldNotPanic)
+			So(actualErr, ShouldBeError, "errorist: wait on nil cmd")
+		})
+
+		Convey("It should do nothing with processes not started or already waited for", func() {
+			So(func() { WaitWithErrCapture(exec.Command("true"), &actualErr) }, ShouldNotPanic)
+
+			cmd := start("exit 1")
+			So(cmd.Wait(), ShouldNotBeNil)
+			WaitWithErrCapture(cmd, &actualErr)
+			So(actualErr, ShouldBeNil)
+		})
+	})
+}
+
+func TestTailWriter(t *testing.T) {
+	Convey("Writing to errorist.TailWriter", t, func() {
+		w := NewTailWriter(5)
+
+		Convey("It should keep only the last bytes", func() {
+			_, _ = w.Write([]byte("abc"))
+			So(w.String(), ShouldEqual, "abc")
+			_, _ = w.Write([]byte("defg"))
+			So(w.String(), ShouldEqual, "cdefg")
+
+			n, err := w.Write([]byte(strings.Repeat("x", 10) + "12345"))
+			So(n, ShouldEqual, 15)
+			So(err, ShouldBeNil)
+			So(w.String(), ShouldEqual, "12345")
+		})
+	})
+}
@@ -0,0 +1,67 @@
+package errorist
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// registry is an immutable set of options registered with SetGlobalOptions and SetPackageLevelOptions.
+// It is replaced as a whole on every update, so it can be read without locks.
+type registry struct {
+	global   []Option
+	packages map[string][]Option
+}
+
+var (
+	// registryMu serializes updates on currentRegistry.
+	registryMu sync.Mutex
+
+	// currentRegistry holds *registry.
+	currentRegistry atomic.Value
+)
+
+func init() {
+	currentRegistry.Store(&registry{packages: map[string][]Option{}})
+}
+
+func loadRegistry() *registry {
+	return currentRegistry.Load().(*registry)
+}
+
+// updateRegistry applies the update on a copy of the current registry and replaces it.
+func updateRegistry(update func(r *registry)) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	cur := loadRegistry()
+	next := &registry{
+		global:   cur.global,
+		packages: make(map[string][]Option, len(cur.packages)),
+	}
+	for pkg, opts := range cur.packages {
+		next.packages[pkg] = opts
+	}
+	update(next)
+	currentRegistry.Store(next)
+}
+
+func copyOptions(opts []Option) []Option {
+	return append([]Option(nil), opts...)
+}
+
+// packageOptions returns options registered on the package and its parent packages,
+// ordered from the outermost one so options on nested packages override them.
+func (r *registry) packageOptions(pkg string) (opts []Option) {
+	var scopes []string
+	for scope := range r.packages {
+		if isSubPackage(pkg, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Slice(scopes, func(i, j int) bool { return len(scopes[i]) < len(scopes[j]) })
+	for _, scope := range scopes {
+		opts = append(opts, r.packages[scope]...)
+	}
+	return opts
+}
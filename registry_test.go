@@ -0,0 +1,111 @@
+package errorist
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	pkgErrors "github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// These tests are meant to be run with the race detector (go test -race).
+func TestRegistryConcurrency(t *testing.T) {
+	defer SetGlobalOptions()
+	defer SetPackageLevelOptions()
+
+	Convey("Configuring and using errorist concurrently", t, func() {
+		const workers = 8
+		const iterations = 200
+
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(3)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < iterations; j++ {
+					SetGlobalOptions(Wrapf("global %d", j), WithMaxFrames(j))
+					RegisterFormatter(fmt.Sprintf("race-%d", i), PrettyFormatter)
+				}
+			}(i)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < iterations; j++ {
+					SetPackageLevelOptions(WithLogHandler(func(string) {}))
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for j := 0; j < iterations; j++ {
+					var err error
+					CloseWithErrCapture(&closerMock{ReturnError: pkgErrors.New("test")}, &err)
+					CloseWithLogOnErr(&closerMock{ReturnError: pkgErrors.New("test")}, WithLogHandler(func(string) {}))
+					func() {
+						defer RecoverWithErrCapture(&err, WithFormat("java"))
+						panicStation()
+					}()
+				}
+			}()
+		}
+		wg.Wait()
+
+		Convey("It should end up with one of the configured options", func() {
+			var err error
+			CloseWithErrCapture(&closerMock{ReturnError: pkgErrors.New("test")}, &err)
+			So(err.Error(), ShouldEqual, fmt.Sprintf("global %d: test", iterations-1))
+		})
+	})
+}
+
+func TestSetGlobalOptions(t *testing.T) {
+	defer SetGlobalOptions()
+
+	Convey("Calling errorist.SetGlobalOptions", t, func() {
+		opts := []Option{Wrapf("first")}
+		SetGlobalOptions(opts...)
+
+		Convey("It should not be affected by later changes on the given slice", func() {
+			opts[0] = Wrapf("second")
+
+			var err error
+			CloseWithErrCapture(&closerMock{ReturnError: pkgErrors.New("test")}, &err)
+			So(err.Error(), ShouldEqual, "first: test")
+		})
+	})
+}
+
+func TestPackageOptions(t *testing.T) {
+	Convey("Resolving package-level options", t, func() {
+		r := &registry{packages: map[string][]Option{
+			"github.com/org/app":    {Wrapf("app"), WithMaxFrames(1)},
+			"github.com/org/app/db": {Wrapf("db")},
+			"github.com/org/apple":  {Wrapf("apple")},
+		}}
+		resolve := func(pkg string) Options {
+			var o Options
+			for _, opt := range r.packageOptions(pkg) {
+				opt(&o)
+			}
+			return o
+		}
+
+		Convey("It should inherit options from parent packages", func() {
+			o := resolve("github.com/org/app/db/sql")
+			So(o.WrapArguments, ShouldResemble, []interface{}{"db"})
+			So(o.MaxFrames, ShouldEqual, 1)
+		})
+
+		Convey("It should not mix up packages sharing a prefix", func() {
+			So(resolve("github.com/org/app").WrapArguments, ShouldResemble, []interface{}{"app"})
+			So(resolve("github.com/org/apple").MaxFrames, ShouldEqual, 0)
+			So(r.packageOptions("github.com/other"), ShouldBeEmpty)
+		})
+	})
+
+	Convey("Parsing package names", t, func() {
+		So(packageName("github.com/org/app.Func"), ShouldEqual, "github.com/org/app")
+		So(packageName("github.com/org/app.(*Server).Handle.func1"), ShouldEqual, "github.com/org/app")
+		So(packageName("main.main"), ShouldEqual, "main")
+		So(packageName("runtime.gopanic"), ShouldEqual, "runtime")
+	})
+}
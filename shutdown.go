@@ -0,0 +1,140 @@
+package errorist
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// shutdownSignals are signals triggering shutdown of RunUntilSignal.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// RunUntilSignal runs the function until it returns, or SIGINT or SIGTERM is received.
+// On a signal, the context given to the function is canceled. A second signal forces the process to exit.
+//
+// After the function returns, cleanups are run in reverse order. Each cleanup should be one of
+// io.Closer, Stopper, func() error or func(context.Context) error. Waiting for the function and
+// running cleanups on shutdown are limited by ShutdownTimeout, which can be set by options on the context.
+//
+// Panics of the function and cleanups are recovered. The returned error joins errors of all of them,
+// except the cancellation of the context by a signal. It is nil on graceful shutdown.
+func RunUntilSignal(ctx context.Context, run func(ctx context.Context) error, cleanups ...interface{}) error {
+	return runUntilSignal(ctx, applyOptions(1, ContextOptions(ctx)), os.Exit, run, cleanups)
+}
+
+// runUntilSignal is RunUntilSignal calling exit on forced shutdown.
+func runUntilSignal(ctx context.Context, opt Options, exit func(code int), run func(ctx context.Context) error, cleanups []interface{}) error {
+	funcs, err := cleanupFuncs(cleanups)
+	if err != nil {
+		return err
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the first signal cancels the context, and the second one forces the process to exit
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, shutdownSignals...)
+	defer signal.Stop(sigs)
+	signalled, stopped := make(chan struct{}), make(chan struct{})
+	defer close(stopped)
+	go func() {
+		select {
+		case <-sigs:
+			// signalled should be closed before the cancellation is seen by run
+			close(signalled)
+			cancel()
+		case <-stopped:
+			return
+		}
+		select {
+		case <-sigs:
+			exit(1)
+		case <-stopped:
+		}
+	}()
+
+	runErr := make(chan error, 1)
+	go func() {
+		var err error
+		defer func() { runErr <- err }()
+		defer RecoverWithErrCaptureCtx(ctx, &err)
+		err = run(runCtx)
+	}()
+
+	var errs []error
+	var shutdownCtx context.Context
+	var cancelShutdown context.CancelFunc
+	select {
+	case err := <-runErr:
+		shutdownCtx, cancelShutdown = context.WithTimeout(context.WithoutCancel(ctx), opt.ShutdownTimeout)
+		errs = append(errs, ignoreSignalCancel(err, signalled))
+
+	case <-signalled:
+		shutdownCtx, cancelShutdown = context.WithTimeout(context.WithoutCancel(ctx), opt.ShutdownTimeout)
+		select {
+		case err := <-runErr:
+			errs = append(errs, ignoreSignalCancel(err, signalled))
+		case <-shutdownCtx.Done():
+			errs = append(errs, errors.WithMessage(shutdownCtx.Err(), "run has not returned on shutdown"))
+		}
+	}
+	defer cancelShutdown()
+
+	for i := len(funcs) - 1; i >= 0; i-- {
+		errs = append(errs, runCleanup(shutdownCtx, funcs[i], cleanups[i]))
+	}
+	return joinErrors(errs...)
+}
+
+// ignoreSignalCancel returns nil if the error is the cancellation of the context by a signal.
+func ignoreSignalCancel(err error, signalled <-chan struct{}) error {
+	select {
+	case <-signalled:
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+	default:
+	}
+	return err
+}
+
+// runCleanup runs the cleanup, giving up if it doesn't finish until the context is done.
+func runCleanup(ctx context.Context, cleanup func(ctx context.Context) error, resource interface{}) error {
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		defer func() { done <- err }()
+		defer RecoverWithErrCaptureCtx(ctx, &err)
+		err = cleanup(ctx)
+	}()
+	select {
+	case err := <-done:
+		return errors.WithMessagef(err, "cleanup %T", resource)
+	case <-ctx.Done():
+		return errors.WithMessagef(ctx.Err(), "cleanup %T", resource)
+	}
+}
+
+func cleanupFuncs(cleanups []interface{}) ([]func(ctx context.Context) error, error) {
+	funcs := make([]func(ctx context.Context) error, len(cleanups))
+	for i, c := range cleanups {
+		switch c := c.(type) {
+		case io.Closer:
+			funcs[i] = func(context.Context) error { return c.Close() }
+		case Stopper:
+			funcs[i] = func(context.Context) error { return c.Stop() }
+		case func() error:
+			funcs[i] = func(context.Context) error { return c() }
+		case func(context.Context) error:
+			funcs[i] = c
+		default:
+			return nil, fmt.Errorf("errorist: unsupported cleanup type %T", c)
+		}
+	}
+	return funcs, nil
+}
@@ -0,0 +1,137 @@
+package errorist
+
+import (
+	"context"
+	stdlibErrors "errors"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	pkgErrors "github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRunUntilSignal(t *testing.T) {
+	Convey("Calling errorist.RunUntilSignal", t, func() {
+		ctx := context.Background()
+		recorder := &cleanupRecorder{}
+		cleanups := []interface{}{&closerMock{}, &stopperMock{}}
+
+		Convey("It should run cleanups in reverse order after the function returns", func() {
+			err := RunUntilSignal(ctx, func(ctx context.Context) error { return nil },
+				func() error { recorder.Record("first"); return nil },
+				func(ctx context.Context) error { recorder.Record("second"); return nil },
+				cleanups[0],
+				cleanups[1],
+			)
+			So(err, ShouldBeNil)
+			So(recorder.Records(), ShouldResemble, []string{"second", "first"})
+			So(cleanups[0].(*closerMock).CloseCalled, ShouldEqual, 1)
+			So(cleanups[1].(*stopperMock).StopCalled, ShouldEqual, 1)
+		})
+
+		Convey("It should join errors of the function and cleanups", func() {
+			runErr := pkgErrors.New("run failed")
+			closeErr := pkgErrors.New("close failed")
+			err := RunUntilSignal(ctx, func(ctx context.Context) error { return runErr }, &closerMock{ReturnError: closeErr})
+			So(stdlibErrors.Is(err, runErr), ShouldBeTrue)
+			So(stdlibErrors.Is(err, closeErr), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, "run failed\ncleanup *errorist.closerMock: close failed")
+		})
+
+		Convey("On a signal, it should cancel the context and shut down gracefully", func() {
+			err := RunUntilSignal(ctx, func(ctx context.Context) error {
+				signalSelf(syscall.SIGTERM)
+				<-ctx.Done()
+				return ctx.Err()
+			}, cleanups...)
+			So(err, ShouldBeNil)
+			So(cleanups[0].(*closerMock).CloseCalled, ShouldEqual, 1)
+			So(cleanups[1].(*stopperMock).StopCalled, ShouldEqual, 1)
+		})
+
+		Convey("On a second signal, it should force the process to exit", func() {
+			exited := make(chan int, 1)
+			exit := func(code int) { exited <- code }
+
+			release := make(chan struct{})
+			go func() {
+				_ = runUntilSignal(ctx, DefaultOptions, exit, func(ctx context.Context) error {
+					signalSelf(syscall.SIGTERM)
+					<-ctx.Done()
+					signalSelf(syscall.SIGTERM)
+					<-release
+					return nil
+				}, nil)
+			}()
+			So(<-exited, ShouldEqual, 1)
+			close(release)
+		})
+
+		Convey("It should recover from panics of the function and cleanups", func() {
+			err := RunUntilSignal(ctx, func(ctx context.Context) error {
+				panicStation()
+				return nil
+			}, func() error { panic("cleanup panic") })
+
+			var pe *PanicError
+			So(stdlibErrors.As(err, &pe), ShouldBeTrue)
+			So(pe.Reason, ShouldEqual, "panic: assignment to entry in nil map")
+			So(err.Error(), ShouldContainSubstring, "cleanup func() error: panic: cleanup panic")
+		})
+
+		Convey("It should give up waiting after ShutdownTimeout", func() {
+			ctx := WithOptions(ctx, WithShutdownTimeout(50*time.Millisecond))
+			release := make(chan struct{})
+			defer close(release)
+
+			err := RunUntilSignal(ctx, func(ctx context.Context) error {
+				signalSelf(syscall.SIGTERM)
+				<-release
+				return nil
+			}, func(ctx context.Context) error {
+				<-release
+				return nil
+			})
+			So(stdlibErrors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+			So(err.Error(), ShouldStartWith, "run has not returned on shutdown: ")
+			So(err.Error(), ShouldContainSubstring, "cleanup func(context.Context) error: context deadline exceeded")
+		})
+
+		Convey("It should reject unsupported cleanups", func() {
+			ran := false
+			err := RunUntilSignal(ctx, func(ctx context.Context) error { ran = true; return nil }, "not a cleanup")
+			So(err, ShouldBeError, "errorist: unsupported cleanup type string")
+			So(ran, ShouldBeFalse)
+		})
+	})
+}
+
+type cleanupRecorder struct {
+	mu      sync.Mutex
+	records []string
+}
+
+func (r *cleanupRecorder) Record(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, name)
+}
+
+func (r *cleanupRecorder) Records() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.records...)
+}
+
+func signalSelf(sig os.Signal) {
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		panic(err)
+	}
+	if err := p.Signal(sig); err != nil {
+		panic(err)
+	}
+}
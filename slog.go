@@ -0,0 +1,98 @@
+package errorist
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LogWithSlog is an option for using log/slog on functions end with "WithErrorLog".
+// Errors are logged at the given level. Please refer SlogLogger for details.
+func LogWithSlog(logger *slog.Logger, level slog.Level) Option {
+	return WithErrorLogger(SlogLogger(logger, level))
+}
+
+// LogWithSlogMinLevel is an option for using log/slog on functions end with "WithErrorLog".
+// Errors are logged at the slog level mapped from their levels, and ones below minLevel are dropped.
+func LogWithSlogMinLevel(logger *slog.Logger, minLevel slog.Level) Option {
+	return WithErrorLogger(SlogLoggerMinLevel(logger, minLevel))
+}
+
+// SlogLogger returns an ErrorLogger using log/slog. Errors are logged with structured attributes
+// grouped under "error": its message, type, operation, resource type, caller, wrap context,
+// fingerprint and stacktrace.
+//
+// Errors are logged at the given level regardless of their levels.
+func SlogLogger(logger *slog.Logger, level slog.Level) ErrorLogger {
+	return &slogLogger{logger: logger, level: func(Level) slog.Level { return level }, minLevel: level}
+}
+
+// SlogLoggerMinLevel is same as SlogLogger, but errors are logged at the slog level
+// mapped from their levels by SlogLevel, and ones below minLevel are dropped.
+func SlogLoggerMinLevel(logger *slog.Logger, minLevel slog.Level) ErrorLogger {
+	return &slogLogger{logger: logger, level: SlogLevel, minLevel: minLevel}
+}
+
+// SlogLevel returns the slog level corresponding to the level.
+func SlogLevel(level Level) slog.Level {
+	switch level {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelWarn:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+type slogLogger struct {
+	logger   *slog.Logger
+	level    func(Level) slog.Level
+	minLevel slog.Level
+}
+
+func (l *slogLogger) LogError(err error, meta LogMeta) {
+	ctx := context.Background()
+	level := l.level(meta.Level)
+	if level < l.minLevel || !l.logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("message", err.Error()),
+		slog.String("type", fmt.Sprintf("%T", errors.Cause(err))),
+		slog.String("operation", string(meta.Operation)),
+	}
+	if meta.ResourceType != "" {
+		attrs = append(attrs, slog.String("resource", meta.ResourceType))
+	}
+	if meta.Caller.Function != "" {
+		attrs = append(attrs, slog.String("caller", meta.Caller.String()))
+	}
+	if meta.Wrap != "" {
+		attrs = append(attrs, slog.String("wrap", meta.Wrap))
+	}
+	attrs = append(attrs, slog.String("fingerprint", Fingerprint(err)))
+	if frames := ErrorFrames(err); len(frames) > 0 {
+		attrs = append(attrs, slog.Any("stack", framesToStrings(frames)))
+	}
+	summary := strings.SplitN(err.Error(), "\n", 2)[0]
+	l.logger.LogAttrs(ctx, level, summary, slog.Attr{
+		Key:   "error",
+		Value: slog.GroupValue(attrs...),
+	})
+}
+
+// LogValue implements slog.LogValuer, so PanicError can be logged as structured attributes.
+func (pe PanicError) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("reason", pe.Reason),
+		slog.Int("goroutine", pe.GoroutineID),
+		slog.String("fingerprint", pe.Fingerprint()),
+		slog.Any("stack", framesToStrings(pe.Frames)),
+	)
+}
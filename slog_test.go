@@ -0,0 +1,105 @@
+package errorist
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	pkgErrors "github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLogWithSlog(t *testing.T) {
+	Convey("Logging errors with LogWithSlog", t, func() {
+		buf := new(bytes.Buffer)
+		logger := slog.New(slog.NewJSONHandler(buf, nil))
+		record := func() (r map[string]interface{}) {
+			So(json.Unmarshal(buf.Bytes(), &r), ShouldBeNil)
+			return r
+		}
+
+		Convey("It should log the error with structured attributes", func() {
+			cause := &customError{msg: "test"}
+			CloseWithLogOnErr(&closerMock{ReturnError: cause}, LogWithSlog(logger, slog.LevelWarn), Wrapf("closing %s", "file"))
+
+			r := record()
+			So(r["level"], ShouldEqual, "WARN")
+			So(r["msg"], ShouldEqual, "closing file: test")
+
+			attrs := r["error"].(map[string]interface{})
+			So(attrs["message"], ShouldEqual, "closing file: test")
+			So(attrs["type"], ShouldEqual, "*errorist.customError")
+			So(attrs["wrap"], ShouldEqual, "closing file")
+			So(attrs["fingerprint"], ShouldNotBeEmpty)
+			So(fmt.Sprint(attrs["stack"]), ShouldContainSubstring, "github.com/therne/errorist.TestLogWithSlog")
+		})
+
+		Convey("It should log recovered panics with their stacktraces", func() {
+			func() {
+				defer RecoverWithErrLog(LogWithSlog(logger, slog.LevelError))
+				panicStation()
+			}()
+
+			r := record()
+			So(r["level"], ShouldEqual, "ERROR")
+			So(r["msg"], ShouldEqual, "panic: assignment to entry in nil map")
+
+			attrs := r["error"].(map[string]interface{})
+			So(attrs["type"], ShouldEqual, "*errorist.PanicError")
+			So(fmt.Sprint(attrs["stack"]), ShouldContainSubstring, "github.com/therne/errorist.panicStation")
+		})
+
+		Convey("It should not log below the level of the logger", func() {
+			CloseWithLogOnErr(&closerMock{ReturnError: pkgErrors.New("test")}, LogWithSlog(logger, slog.LevelDebug))
+			So(buf.Len(), ShouldEqual, 0)
+		})
+
+		Convey("It should log at the given level regardless of LogAt", func() {
+			CloseWithLogOnErr(&closerMock{ReturnError: pkgErrors.New("test")}, LogWithSlog(logger, slog.LevelWarn), LogAt(LevelDebug))
+			So(record()["level"], ShouldEqual, "WARN")
+		})
+
+		Convey("With LogWithSlogMinLevel, it should log at the level given by LogAt", func() {
+			CloseWithLogOnErr(&closerMock{ReturnError: pkgErrors.New("test")}, LogWithSlogMinLevel(logger, slog.LevelDebug), LogAt(LevelInfo))
+			So(record()["level"], ShouldEqual, "INFO")
+		})
+
+		Convey("With LogWithSlogMinLevel, it should not log below the minimum level", func() {
+			CloseWithLogOnErr(&closerMock{ReturnError: pkgErrors.New("test")}, LogWithSlogMinLevel(logger, slog.LevelError))
+			So(buf.Len(), ShouldEqual, 0)
+
+			CloseWithLogOnErr(&closerMock{ReturnError: pkgErrors.New("test")}, LogWithSlogMinLevel(logger, slog.LevelDebug), LogAt(LevelDebug))
+			So(buf.Len(), ShouldEqual, 0)
+		})
+
+		Convey("It should be overridden by latter loggers", func() {
+			var logged string
+			CloseWithLogOnErr(
+				&closerMock{ReturnError: pkgErrors.New("test")},
+				LogWithSlog(logger, slog.LevelWarn),
+				WithLogHandler(func(err string) { logged = err }),
+			)
+			So(buf.Len(), ShouldEqual, 0)
+			So(logged, ShouldEqual, "test")
+		})
+	})
+
+	Convey("Logging PanicError with slog", t, func() {
+		buf := new(bytes.Buffer)
+		logger := slog.New(slog.NewJSONHandler(buf, nil))
+		pe := panicWithValue(1)
+		logger.Error("recovered", "panic", pe)
+
+		Convey("It should be logged as a group", func() {
+			var r map[string]interface{}
+			So(json.Unmarshal(buf.Bytes(), &r), ShouldBeNil)
+
+			attrs := r["panic"].(map[string]interface{})
+			So(attrs["reason"], ShouldEqual, "panic: value 1")
+			So(attrs["fingerprint"], ShouldEqual, pe.Fingerprint())
+			So(attrs["stack"].([]interface{})[0], ShouldStartWith, "github.com/therne/errorist.panicWithValue")
+		})
+	})
+}
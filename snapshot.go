@@ -0,0 +1,82 @@
+package errorist
+
+import "sort"
+
+// OptionsSnapshot is a state of global and package-level options taken by Snapshot.
+type OptionsSnapshot struct {
+	r *registry
+}
+
+// Snapshot returns current global and package-level options, which can be restored later with Restore.
+func Snapshot() OptionsSnapshot {
+	return OptionsSnapshot{r: loadRegistry()}
+}
+
+// Restore replaces global and package-level options with ones on the snapshot.
+// Restoring zero OptionsSnapshot clears all options.
+func Restore(s OptionsSnapshot) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if s.r == nil {
+		currentRegistry.Store(&registry{packages: map[string][]Option{}})
+		return
+	}
+	currentRegistry.Store(s.r)
+}
+
+// Global returns global options on the snapshot applied on DefaultOptions.
+func (s OptionsSnapshot) Global() Options {
+	o := DefaultOptions
+	if s.r != nil {
+		for _, optFn := range s.r.global {
+			optFn(&o)
+		}
+	}
+	return o
+}
+
+// Package returns options effective in given package on the snapshot,
+// including global options and options inherited from parent packages.
+func (s OptionsSnapshot) Package(pkg string) Options {
+	o := s.Global()
+	if s.r != nil {
+		for _, optFn := range s.r.packageOptions(pkg) {
+			optFn(&o)
+		}
+	}
+	return o
+}
+
+// Packages returns sorted names of packages having package-level options on the snapshot.
+func (s OptionsSnapshot) Packages() (pkgs []string) {
+	if s.r == nil {
+		return nil
+	}
+	for pkg := range s.r.packages {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+	return pkgs
+}
+
+// TestingT is a subset of testing.TB used by ScopedOptions.
+// It keeps the testing package out of programs importing errorist.
+type TestingT interface {
+	Helper()
+	Cleanup(func())
+}
+
+// ScopedOptions adds given options to global options during the test,
+// and restores the former options when the test and its subtests complete.
+// As global options are shared, tests using it should not run in parallel.
+func ScopedOptions(t TestingT, opts ...Option) {
+	t.Helper()
+
+	snapshot := Snapshot()
+	opts = copyOptions(opts)
+	updateRegistry(func(r *registry) {
+		r.global = append(copyOptions(r.global), opts...)
+	})
+	t.Cleanup(func() { Restore(snapshot) })
+}
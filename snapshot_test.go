@@ -0,0 +1,59 @@
+package errorist
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSnapshot(t *testing.T) {
+	defer Restore(Snapshot())
+
+	Convey("Taking a snapshot of options", t, func() {
+		SetGlobalOptions(WithMaxFrames(10))
+		SetPackageLevelOptions(WithSkipFrames(1))
+		snapshot := Snapshot()
+
+		Convey("It should describe current options", func() {
+			So(snapshot.Global().MaxFrames, ShouldEqual, 10)
+			So(snapshot.Global().SkipFrames, ShouldEqual, 0)
+			So(snapshot.Packages(), ShouldResemble, []string{"github.com/therne/errorist"})
+			So(snapshot.Package("github.com/therne/errorist/sub").SkipFrames, ShouldEqual, 1)
+		})
+
+		Convey("It should be restored after changes", func() {
+			SetGlobalOptions(WithMaxFrames(20))
+			SetPackageLevelOptions()
+			So(EffectiveOptions().MaxFrames, ShouldEqual, 20)
+
+			Restore(snapshot)
+			So(EffectiveOptions().MaxFrames, ShouldEqual, 10)
+			So(EffectiveOptions().SkipFrames, ShouldEqual, 1)
+		})
+
+		Convey("Restoring zero snapshot, it should clear all options", func() {
+			Restore(OptionsSnapshot{})
+			So(EffectiveOptions().MaxFrames, ShouldEqual, DefaultOptions.MaxFrames)
+			So(Snapshot().Packages(), ShouldBeEmpty)
+		})
+	})
+}
+
+func TestScopedOptions(t *testing.T) {
+	defer SetGlobalOptions()
+	SetGlobalOptions(WithMaxFrames(10))
+
+	t.Run("scoped", func(t *testing.T) {
+		ScopedOptions(t, WithSkipFrames(3))
+
+		Convey("Within the test, it should apply given options on top of global options", t, func() {
+			So(EffectiveOptions().MaxFrames, ShouldEqual, 10)
+			So(EffectiveOptions().SkipFrames, ShouldEqual, 3)
+		})
+	})
+
+	Convey("After the test, it should restore former options", t, func() {
+		So(EffectiveOptions().MaxFrames, ShouldEqual, 10)
+		So(EffectiveOptions().SkipFrames, ShouldEqual, 0)
+	})
+}
@@ -3,7 +3,7 @@ package errorist
 import (
 	"bytes"
 	"fmt"
-	"os"
+	"io"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -12,18 +12,16 @@ import (
 	"github.com/pkg/errors"
 )
 
-const maxTraces = 300
-
 type stackTracer interface {
 	StackTrace() errors.StackTrace
 }
 
 // Stacktrace returns pretty-formatted stack trace of an error created or wrapped by
 // `github.com/pkg/errors` package. Runtime stack traces are skipped for simplicity.
-func Stacktrace(err error, _ ...Option) (traceEntries []string) {
+func Stacktrace(err error, opts ...Option) (traceEntries []string) {
 	tr, ok := err.(stackTracer)
 	if !ok {
-		return []string{callerTrace(1)}
+		return []string{callerTrace(1 + applyOptions(1, opts).CallerSkip)}
 	}
 	for _, t := range tr.StackTrace() {
 		trace := fmt.Sprintf("%+v", t)
@@ -39,36 +37,45 @@ func Stacktrace(err error, _ ...Option) (traceEntries []string) {
 	return traceEntries
 }
 
-func stacktrace(skip, limit int, opts Options) []string {
-	if opts.DetailedStacktrace {
-		return detailedStacktrace(skip+1, limit, opts)
+// ErrorFrames returns the stacktrace of an error recovered from a panic, or created or wrapped by
+// `github.com/pkg/errors` package. Runtime frames are skipped. Returns nil if the error has no stacktrace.
+func ErrorFrames(err error) (frames []Frame) {
+	var pe *PanicError
+	if errors.As(err, &pe) {
+		return pe.Frames
+	}
+	tr := innermostStackTracer(err)
+	if tr == nil {
+		return nil
 	}
-	return simpleStacktrace(skip+1, limit, opts)
+	for _, frame := range stackTracerFrames(tr) {
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			frames = append(frames, frame)
+		}
+	}
+	return frames
 }
 
-func simpleStacktrace(skip, limit int, opts Options) (traces []string) {
-	goPaths := getGOPATHs()
+func framesToStrings(frames []Frame) []string {
+	traces := make([]string, len(frames))
+	for i, frame := range frames {
+		traces[i] = frame.String()
+	}
+	return traces
+}
 
-	pc := make([]uintptr, maxTraces)
-	n := runtime.Callers(2+skip, pc)
-	if n == 0 {
+func simpleStacktrace(skip, limit int, opts Options) (traces []string) {
+	frames := compactFrames(callerFrames(skip+1, opts), limit)
+	if len(frames) == 0 {
 		return []string{"unknown"}
 	}
-	frames := runtime.CallersFrames(pc)
-	for i := 0; i < limit; i++ {
-		frame, more := frames.Next()
-		if !more {
-			break
-		}
-		if opts.SkipNonProjectFiles && isNonProjectFile(goPaths, frame.File) {
-			continue
-		}
-		st := fmt.Sprintf("%s (%s:%d)", frame.Function, filepath.Base(frame.File), frame.Line)
-		traces = append(traces, st)
+	for _, frame := range frames {
+		traces = append(traces, frame.String())
 	}
 	return traces
 }
 
+// detailedStacktrace dumps the running goroutine. Like simple traces, SkipFrames and limit are applied on its frames.
 func detailedStacktrace(skip, limit int, opts Options) (traces []string) {
 	st := make([]byte, 1024)
 	for {
@@ -79,7 +86,7 @@ func detailedStacktrace(skip, limit int, opts Options) (traces []string) {
 		}
 		st = make([]byte, 2*len(st))
 	}
-	c, err := stack.ParseDump(bytes.NewReader(st), os.Stdout, true)
+	c, err := stack.ParseDump(bytes.NewReader(normalizeDump(st)), io.Discard, true)
 	if err != nil {
 		return append(
 			simpleStacktrace(skip+1, limit, opts),
@@ -103,15 +110,9 @@ func detailedStacktrace(skip, limit int, opts Options) (traces []string) {
 
 	for i, bucket := range buckets {
 		curLine := ""
-		panicIndex := -1
-		for i, call := range bucket.Stack.Calls {
-			if call.Func.Name() == "panic" {
-				panicIndex = i
-			}
-		}
 		if i == 0 {
 			// remove stacks before main panic
-			bucket.Stack.Calls = bucket.Stack.Calls[skip+panicIndex+1:]
+			bucket.Stack.Calls = trimPanicCalls(bucket.Stack.Calls, skip+1)
 		}
 
 		// Print the goroutine header.
@@ -141,21 +142,30 @@ func detailedStacktrace(skip, limit int, opts Options) (traces []string) {
 		}
 
 		curLine += fmt.Sprintf("%s: %s %s", goroutineIDs, bucket.State, extra)
-		if len(traces) >= maxTraces {
-			traces = append(traces, curLine+" (...)")
-			continue
-		}
 		traces = append(traces, curLine)
 
-		// Print the stack lines.
+		var frames []Frame
 		for _, line := range bucket.Stack.Calls {
-			if opts.SkipNonProjectFiles && isNonProjectFile(goPaths, line.LocalSrcPath) {
+			frame := Frame{Function: line.Func.Raw, File: line.SrcPath, Line: line.Line}
+			if !isSkippedFrame(frame, goPaths, opts) {
+				frames = append(frames, frame)
+			}
+		}
+		if i == 0 {
+			frames = dropFrames(frames, opts.SkipFrames)
+		}
+
+		// Print the stack lines.
+		for _, frame := range compactFrames(frames, limit) {
+			if frame.Elided != "" {
+				traces = append(traces, "    "+frame.Elided)
 				continue
 			}
+			fn := stack.Func{Raw: frame.Function}
 			traces = append(traces, fmt.Sprintf(
-				"    %-*s  %s(%s)",
-				srcLen, line.SrcLine(),
-				line.Func.PkgDotName(), &line.Args,
+				"    %-*s  %s()",
+				srcLen, fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line),
+				fn.PkgDotName(),
 			))
 		}
 		if bucket.Stack.Elided {
@@ -165,6 +175,39 @@ func detailedStacktrace(skip, limit int, opts Options) (traces []string) {
 	return traces
 }
 
+// trimPanicCalls removes calls above the panic, so the trace starts from where the panic has been raised.
+// runtime.gopanic is printed as "panic" on goroutine dumps.
+// Outside of a panic, given number of calls are removed instead.
+func trimPanicCalls(calls []stack.Call, skip int) []stack.Call {
+	for i, call := range calls {
+		if call.Func.Raw == "panic" || call.Func.Raw == "runtime.gopanic" {
+			return calls[i+1:]
+		}
+	}
+	if skip > len(calls) {
+		skip = len(calls)
+	}
+	return calls[skip:]
+}
+
+// isSkippedFrame returns whether the frame is excluded from stacktraces,
+// which is decided by IncludedPackages if set, or by SkipNonProjectFiles otherwise.
+func isSkippedFrame(frame Frame, goPaths []string, opts Options) bool {
+	if len(opts.IncludedPackages) > 0 {
+		return !hasIncludedPackage(frame.Function, opts.IncludedPackages)
+	}
+	return opts.SkipNonProjectFiles && isNonProjectFile(goPaths, frame.File)
+}
+
+func hasIncludedPackage(function string, pkgs []string) bool {
+	for _, pkg := range pkgs {
+		if strings.HasPrefix(function, pkg) {
+			return true
+		}
+	}
+	return false
+}
+
 func isNonProjectFile(goPaths []string, absSrcPath string) bool {
 	for _, gopath := range goPaths {
 		goModRoot := filepath.Join(gopath, "pkg/mod")
@@ -1,16 +1,18 @@
 package errorist
 
+import "context"
+
 type Stopper interface {
 	Stop() error
 }
 
 // StopWithErrCapture is used if you want to Stop and fail the function or
 // method on a `Stop()` error (make sure the `error` return argument is
-// named as `err`). If the error is already present, `StopWithErrCapture`
-// will append the error caused by `Stop` if any.
+// named as `err`). If the error is already present, it is kept and
+// the error caused by `Stop` is discarded, as the former is likely the cause.
 func StopWithErrCapture(c Stopper, capture *error, opts ...Option) {
-	if err := c.Stop(); err != nil && *capture != nil {
-		*capture = maybeWrap(err, applyOptions(opts))
+	if err := c.Stop(); err != nil && *capture == nil {
+		*capture = maybeWrap(err, applyOptions(1, opts))
 	}
 }
 
@@ -20,7 +22,7 @@ func StopWithErrCapture(c Stopper, capture *error, opts ...Option) {
 // will send the error to the given channel caused by `Stop` if any.
 func StopWithErrChan(c Stopper, errChan chan<- error, opts ...Option) {
 	if err := c.Stop(); err != nil {
-		errChan <- maybeWrap(err, applyOptions(opts))
+		errChan <- maybeWrap(err, applyOptions(1, opts))
 	}
 }
 
@@ -30,7 +32,29 @@ func StopWithErrChan(c Stopper, errChan chan<- error, opts ...Option) {
 // will log the error caused by `Stop` if any.
 func StopWithErrLog(c Stopper, opts ...Option) {
 	if err := c.Stop(); err != nil {
-		opt := applyOptions(opts)
-		opt.Logger(maybeWrap(err, opt).Error())
+		opt := applyOptions(1, opts)
+		logError(maybeWrap(err, opt), newLogMeta(OperationStop, c, opt), opt)
+	}
+}
+
+// StopWithErrCaptureCtx is same as StopWithErrCapture, with options carried by the context.
+func StopWithErrCaptureCtx(ctx context.Context, c Stopper, capture *error, opts ...Option) {
+	if err := c.Stop(); err != nil && *capture == nil {
+		*capture = maybeWrap(err, applyOptions(1, withContextOptions(ctx, opts)))
+	}
+}
+
+// StopWithErrChanCtx is same as StopWithErrChan, with options carried by the context.
+func StopWithErrChanCtx(ctx context.Context, c Stopper, errChan chan<- error, opts ...Option) {
+	if err := c.Stop(); err != nil {
+		errChan <- maybeWrap(err, applyOptions(1, withContextOptions(ctx, opts)))
+	}
+}
+
+// StopWithErrLogCtx is same as StopWithErrLog, with options carried by the context.
+func StopWithErrLogCtx(ctx context.Context, c Stopper, opts ...Option) {
+	if err := c.Stop(); err != nil {
+		opt := applyOptions(1, withContextOptions(ctx, opts))
+		logError(maybeWrap(err, opt), newLogMeta(OperationStop, c, opt), opt)
 	}
 }
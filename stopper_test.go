@@ -0,0 +1,53 @@
+package errorist
+
+import (
+	"context"
+	"testing"
+
+	pkgErrors "github.com/pkg/errors"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStopWithErrCapture(t *testing.T) {
+	Convey("Calling errorist.StopWithErrCapture", t, func() {
+		expectedErr := pkgErrors.New("test")
+		var actualErr error
+
+		Convey("It should capture error caused while stopping", func() {
+			m := &stopperMock{ReturnError: expectedErr}
+			StopWithErrCapture(m, &actualErr)
+
+			So(m.StopCalled, ShouldEqual, 1)
+			So(actualErr, ShouldEqual, expectedErr)
+		})
+
+		Convey("It should not capture error if underlying error is already present", func() {
+			actualErr = pkgErrors.New("already present")
+
+			m := &stopperMock{ReturnError: expectedErr}
+			StopWithErrCapture(m, &actualErr)
+
+			So(m.StopCalled, ShouldEqual, 1)
+			So(actualErr, ShouldBeError, "already present")
+		})
+
+		Convey("It should behave same with StopWithErrCaptureCtx", func() {
+			StopWithErrCaptureCtx(context.Background(), &stopperMock{ReturnError: expectedErr}, &actualErr)
+			So(actualErr, ShouldEqual, expectedErr)
+
+			StopWithErrCaptureCtx(context.Background(), &stopperMock{ReturnError: pkgErrors.New("other")}, &actualErr)
+			So(actualErr, ShouldEqual, expectedErr)
+		})
+	})
+}
+
+type stopperMock struct {
+	StopCalled  int
+	ReturnError error
+}
+
+func (m *stopperMock) Stop() error {
+	m.StopCalled++
+	return m.ReturnError
+}
@@ -0,0 +1,71 @@
+package errorist
+
+import (
+	"context"
+	"database/sql"
+	stdlibErrors "errors"
+
+	"github.com/pkg/errors"
+)
+
+// Tx runs the function in a transaction. The transaction is committed if the function succeeds,
+// and rolled back if it returns an error or panics. On panics, it is re-panicked after rolling back.
+//
+// Errors on rolling back are joined with the error returned by the function.
+func Tx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error, opts ...Option) (err error) {
+	opt := applyOptions(1, opts)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return maybeWrap(errors.WithMessage(err, "begin transaction"), opt)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			if err := rollback(tx); err != nil {
+				logError(maybeWrap(err, opt), newLogMeta(OperationRollback, tx, opt), opt)
+			}
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := rollback(tx); rbErr != nil {
+			err = stdlibErrors.Join(err, rbErr)
+		}
+		return maybeWrap(err, opt)
+	}
+	if err := tx.Commit(); err != nil {
+		return maybeWrap(errors.WithMessage(err, "commit transaction"), opt)
+	}
+	return nil
+}
+
+// RollbackUnlessCommitted is used with `defer` if you want to roll back the transaction
+// unless it has been committed, i.e. on returning an error or panicking (make sure the `error`
+// return argument is named as `err`). An error caused by `Rollback` is joined with the captured error.
+//
+// On panics, it is re-panicked after rolling back, and an error on rolling back is logged
+// with the logger on options since it can't be captured.
+func RollbackUnlessCommitted(tx *sql.Tx, capture *error, opts ...Option) {
+	if r := recover(); r != nil {
+		if err := rollback(tx); err != nil {
+			opt := applyOptions(1, opts)
+			logError(maybeWrap(err, opt), newLogMeta(OperationRollback, tx, opt), opt)
+		}
+		panic(r)
+	}
+	if err := rollback(tx); err != nil {
+		err = maybeWrap(err, applyOptions(1, opts))
+		if *capture != nil {
+			err = stdlibErrors.Join(*capture, err)
+		}
+		*capture = err
+	}
+}
+
+// rollback rolls back the transaction. It returns nil if the transaction has been already committed or rolled back.
+func rollback(tx *sql.Tx) error {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return errors.WithMessage(err, "rollback transaction")
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package errorist
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	stdlibErrors "errors"
+	"sync"
+	"testing"
+
+	pkgErrors "github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTx(t *testing.T) {
+	Convey("Calling errorist.Tx", t, func() {
+		db, drv := openMockDB()
+		ctx := context.Background()
+
+		Convey("It should commit if the function succeeds", func() {
+			err := Tx(ctx, db, func(tx *sql.Tx) error { return nil })
+			So(err, ShouldBeNil)
+			So(drv.Commits(), ShouldEqual, 1)
+			So(drv.Rollbacks(), ShouldEqual, 0)
+		})
+
+		Convey("It should roll back if the function fails", func() {
+			fnErr := pkgErrors.New("insert failed")
+			err := Tx(ctx, db, func(tx *sql.Tx) error { return fnErr }, Wrapf("creating user"))
+			So(err.Error(), ShouldEqual, "creating user: insert failed")
+			So(stdlibErrors.Is(err, fnErr), ShouldBeTrue)
+			So(drv.Commits(), ShouldEqual, 0)
+			So(drv.Rollbacks(), ShouldEqual, 1)
+		})
+
+		Convey("It should join errors on rolling back", func() {
+			fnErr := pkgErrors.New("insert failed")
+			drv.SetRollbackError(driver.ErrBadConn)
+			err := Tx(ctx, db, func(tx *sql.Tx) error { return fnErr })
+			So(stdlibErrors.Is(err, fnErr), ShouldBeTrue)
+			So(stdlibErrors.Is(err, driver.ErrBadConn), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, "rollback transaction: ")
+		})
+
+		Convey("It should roll back and re-panic if the function panics", func() {
+			So(func() {
+				_ = Tx(ctx, db, func(tx *sql.Tx) error { panic("tx panic") })
+			}, ShouldPanicWith, "tx panic")
+			So(drv.Commits(), ShouldEqual, 0)
+			So(drv.Rollbacks(), ShouldEqual, 1)
+		})
+
+		Convey("It should log errors on rolling back while panicking", func() {
+			logger := &errorLoggerMock{}
+			drv.SetRollbackError(driver.ErrBadConn)
+			So(func() {
+				_ = Tx(ctx, db, func(tx *sql.Tx) error { panic("tx panic") }, WithErrorLogger(logger))
+			}, ShouldPanicWith, "tx panic")
+			So(logger.Entries(), ShouldHaveLength, 1)
+			So(stdlibErrors.Is(logger.Entries()[0].err, driver.ErrBadConn), ShouldBeTrue)
+			So(logger.Entries()[0].meta.Operation, ShouldEqual, OperationRollback)
+		})
+
+		Convey("It should return errors on committing", func() {
+			drv.SetCommitError(driver.ErrBadConn)
+			err := Tx(ctx, db, func(tx *sql.Tx) error { return nil })
+			So(stdlibErrors.Is(err, driver.ErrBadConn), ShouldBeTrue)
+			So(err.Error(), ShouldStartWith, "commit transaction: ")
+		})
+	})
+}
+
+func TestRollbackUnlessCommitted(t *testing.T) {
+	Convey("Calling errorist.RollbackUnlessCommitted", t, func() {
+		db, drv := openMockDB()
+		run := func(fn func(tx *sql.Tx) error, opts ...Option) (err error) {
+			tx, err := db.Begin()
+			if err != nil {
+				return err
+			}
+			defer RollbackUnlessCommitted(tx, &err, opts...)
+			return fn(tx)
+		}
+
+		Convey("It should not roll back committed transactions", func() {
+			err := run(func(tx *sql.Tx) error { return tx.Commit() })
+			So(err, ShouldBeNil)
+			So(drv.Commits(), ShouldEqual, 1)
+			So(drv.Rollbacks(), ShouldEqual, 0)
+		})
+
+		Convey("It should roll back on errors", func() {
+			fnErr := pkgErrors.New("insert failed")
+			err := run(func(tx *sql.Tx) error { return fnErr })
+			So(err, ShouldEqual, fnErr)
+			So(drv.Rollbacks(), ShouldEqual, 1)
+		})
+
+		Convey("It should join errors on rolling back with the captured error", func() {
+			fnErr := pkgErrors.New("insert failed")
+			drv.SetRollbackError(driver.ErrBadConn)
+			err := run(func(tx *sql.Tx) error { return fnErr }, Wrapf("creating user"))
+			So(stdlibErrors.Is(err, fnErr), ShouldBeTrue)
+			So(stdlibErrors.Is(err, driver.ErrBadConn), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, "insert failed\ncreating user: rollback transaction: "+driver.ErrBadConn.Error())
+		})
+
+		Convey("It should roll back and re-panic on panics", func() {
+			So(func() {
+				_ = run(func(tx *sql.Tx) error { panic("tx panic") })
+			}, ShouldPanicWith, "tx panic")
+			So(drv.Rollbacks(), ShouldEqual, 1)
+		})
+	})
+}
+
+// openMockDB opens a database with a driver counting commits and rollbacks.
+func openMockDB() (*sql.DB, *txDriverMock) {
+	drv := &txDriverMock{}
+	db := sql.OpenDB(drv)
+	Reset(func() { _ = db.Close() })
+	return db, drv
+}
+
+type txDriverMock struct {
+	mu                       sync.Mutex
+	commits, rollbacks       int
+	commitErr, rollbackError error
+}
+
+func (d *txDriverMock) Connect(context.Context) (driver.Conn, error) { return &txConnMock{d}, nil }
+func (d *txDriverMock) Driver() driver.Driver                        { return nil }
+
+func (d *txDriverMock) Commits() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.commits
+}
+
+func (d *txDriverMock) Rollbacks() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.rollbacks
+}
+
+func (d *txDriverMock) SetCommitError(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.commitErr = err
+}
+
+func (d *txDriverMock) SetRollbackError(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.rollbackError = err
+}
+
+type txConnMock struct {
+	d *txDriverMock
+}
+
+func (c *txConnMock) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *txConnMock) Close() error                        { return nil }
+func (c *txConnMock) Begin() (driver.Tx, error)           { return c, nil }
+
+func (c *txConnMock) Commit() error {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.commits++
+	return c.d.commitErr
+}
+
+func (c *txConnMock) Rollback() error {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.rollbacks++
+	return c.d.rollbackError
+}
@@ -1,31 +1,84 @@
 package errorist
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
-
-	"github.com/pkg/errors"
 )
 
+// callerPackageName returns the package of the caller, skipping frames of the Go runtime.
+//
+// If the caller is deferred and run by a panic, the frames right above it are the panicking ones,
+// not the function which has deferred the call. As the runtime doesn't tell which of them deferred it,
+// the nearest package having package-level options is chosen, falling back to the panicking package.
 func callerPackageName(skip int) string {
-	pc := make([]uintptr, 1)
+	pc := make([]uintptr, 64)
 	n := runtime.Callers(2+skip, pc)
-	if n == 0 {
+	frames := runtime.CallersFrames(pc[:n])
+
+	var (
+		fallback  string
+		panicking bool
+	)
+	for more := n > 0; more; {
+		var frame runtime.Frame
+		frame, more = frames.Next()
+		pkg := packageName(frame.Function)
+		if pkg == "" {
+			pkg = frame.File
+		}
+		if pkg == "runtime" {
+			panicking = panicking || frame.Function == "runtime.gopanic"
+			continue
+		}
+		if !panicking || len(loadRegistry().packageOptions(pkg)) > 0 {
+			return pkg
+		}
+		if fallback == "" {
+			fallback = pkg
+		}
+	}
+	if fallback == "" {
 		return "unknown"
 	}
-	frame, _ := runtime.CallersFrames(pc).Next()
-	frags := strings.Split(frame.Function, ".")
-	if len(frags) == 0 {
-		if frame.Function != "" {
-			return frame.Function
+	return fallback
+}
+
+// callerFrame returns the frame of the caller, skipping frames of the Go runtime
+// so deferred calls run by a panic resolve to where the panic has been raised.
+func callerFrame(skip int) Frame {
+	pc := make([]uintptr, 32)
+	n := runtime.Callers(2+skip, pc)
+	if n == 0 {
+		return Frame{}
+	}
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		frame, more := frames.Next()
+		if packageName(frame.Function) != "runtime" || !more {
+			return Frame{Function: frame.Function, File: frame.File, Line: frame.Line}
 		}
-		return frame.File
 	}
-	return strings.Join(frags[:len(frags)-1], ".")
+}
+
+// packageName returns the package path of a fully-qualified function name
+// (e.g. "github.com/some/app" for "github.com/some/app.(*Server).Handle.func1").
+func packageName(funcName string) string {
+	slash := strings.LastIndex(funcName, "/")
+	if dot := strings.Index(funcName[slash+1:], "."); dot >= 0 {
+		return funcName[:slash+1+dot]
+	}
+	return funcName
+}
+
+// isSubPackage returns whether the package is same as or nested under the parent package.
+func isSubPackage(pkg, parent string) bool {
+	return pkg == parent || strings.HasPrefix(pkg, parent+"/")
 }
 
 func callerTrace(skip int) string {
@@ -38,19 +91,16 @@ func callerTrace(skip int) string {
 	return fmt.Sprintf("%s (%s:%d)", frame.Function, filepath.Base(frame.File), frame.Line)
 }
 
-func maybeWrap(err error, opts Options) error {
-	if err == nil {
-		return nil
-	}
-	if len(opts.WrapArguments) == 0 {
-		return err
-	}
-	switch format := opts.WrapArguments[0].(type) {
-	case string:
-		return errors.Wrapf(err, format, opts.WrapArguments[1:]...)
-	default:
-		return errors.Wrap(err, fmt.Sprint(opts.WrapArguments...))
+// currentGoroutineID returns an ID of the running goroutine, parsed from its stack header.
+func currentGoroutineID() int {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i > 0 {
+		buf = buf[:i]
 	}
+	id, _ := strconv.Atoi(string(buf))
+	return id
 }
 
 // getGOPATHs returns parsed GOPATH or its default, using "/" as path separator.
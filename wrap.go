@@ -0,0 +1,113 @@
+package errorist
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// wrapStackDepth is the maximum depth of stacktraces captured by WrapWithStack.
+const wrapStackDepth = 32
+
+// maybeWrap wraps the error with WrapArguments if set, in the mode specified on options.
+// It must be called directly by the errorist function called by the user,
+// so stacktraces captured by WrapWithStack can start from the user.
+func maybeWrap(err error, opts Options) error {
+	if err == nil {
+		return nil
+	}
+	if len(opts.WrapArguments) == 0 {
+		return err
+	}
+	msg := wrapMessage(opts)
+	switch {
+	case opts.WrapWithStack:
+		pc := make([]uintptr, wrapStackDepth)
+		n := runtime.Callers(3+opts.CallerSkip, pc)
+		return &stackError{wrapError: wrapError{msg: msg, err: err}, pc: pc[:n]}
+
+	case opts.WrapWithFmtErrorf:
+		return &wrapError{msg: msg, err: err}
+
+	default:
+		return errors.Wrap(err, msg)
+	}
+}
+
+// wrapMessage returns the message formatted with WrapArguments.
+func wrapMessage(opts Options) string {
+	if len(opts.WrapArguments) == 0 {
+		return ""
+	}
+	if format, ok := opts.WrapArguments[0].(string); ok {
+		return fmt.Sprintf(format, opts.WrapArguments[1:]...)
+	}
+	return fmt.Sprint(opts.WrapArguments...)
+}
+
+// wrapError is an error wrapped with a message, same as fmt.Errorf("msg: %w", err).
+// Unlike errors created by fmt.Errorf, its cause can be also retrieved with errors.Cause of pkg/errors.
+type wrapError struct {
+	msg string
+	err error
+}
+
+func (e *wrapError) Error() string {
+	return e.msg + ": " + e.err.Error()
+}
+
+func (e *wrapError) Unwrap() error {
+	return e.err
+}
+
+func (e *wrapError) Cause() error {
+	return e.err
+}
+
+// stackError is an error wrapped with a message and a stacktrace captured by errorist.
+type stackError struct {
+	wrapError
+	pc []uintptr
+}
+
+// StackTrace returns the stacktrace in the form of pkg/errors, so it can be read with Stacktrace.
+func (e *stackError) StackTrace() errors.StackTrace {
+	st := make(errors.StackTrace, len(e.pc))
+	for i, pc := range e.pc {
+		st[i] = errors.Frame(pc)
+	}
+	return st
+}
+
+// Frames returns function calls where the error has been wrapped, starting from the innermost one.
+func (e *stackError) Frames() (frames []Frame) {
+	callers := runtime.CallersFrames(e.pc)
+	for {
+		frame, more := callers.Next()
+		if frame.Function != "" {
+			frames = append(frames, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		}
+		if !more {
+			return frames
+		}
+	}
+}
+
+// Format prints the stacktrace along with the message on "%+v".
+func (e *stackError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		lines := []string{e.Error()}
+		for _, frame := range e.Frames() {
+			lines = append(lines, strings.Repeat(" ", 4)+frame.String())
+		}
+		_, _ = io.WriteString(s, strings.Join(lines, "\n"))
+	case verb == 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Error())
+	default:
+		_, _ = io.WriteString(s, e.Error())
+	}
+}
@@ -0,0 +1,88 @@
+package errorist
+
+import (
+	stdlibErrors "errors"
+	"fmt"
+	"testing"
+
+	pkgErrors "github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWrapModes(t *testing.T) {
+	modes := []struct {
+		name     string
+		option   Option
+		hasStack bool
+	}{
+		{name: "pkg/errors (default)", option: func(*Options) {}, hasStack: true},
+		{name: "WrapWithFmtErrorf", option: WrapWithFmtErrorf(), hasStack: false},
+		{name: "WrapWithStack", option: WrapWithStack(), hasStack: true},
+	}
+
+	for _, mode := range modes {
+		mode := mode
+		Convey("Wrapping errors with "+mode.name, t, func() {
+			cause := &customError{msg: "test"}
+			var err error
+			CloseWithErrCapture(&closerMock{ReturnError: cause}, &err, Wrapf("closing %s", "some"), mode.option)
+
+			Convey("It should add the message", func() {
+				So(err.Error(), ShouldEqual, "closing some: test")
+				So(fmt.Sprintf("%v", err), ShouldEqual, "closing some: test")
+			})
+
+			Convey("It should work with errors.Is, errors.As and errors.Cause", func() {
+				So(stdlibErrors.Is(err, cause), ShouldBeTrue)
+
+				var target *customError
+				So(stdlibErrors.As(err, &target), ShouldBeTrue)
+				So(target, ShouldEqual, cause)
+
+				So(pkgErrors.Cause(err), ShouldEqual, cause)
+			})
+
+			Convey("It should have stacktrace only on modes attaching it", func() {
+				_, ok := err.(stackTracer)
+				So(ok, ShouldEqual, mode.hasStack)
+			})
+		})
+	}
+
+	Convey("Wrapping errors with WrapWithStack", t, func() {
+		var err error
+		CloseWithErrCapture(&closerMock{ReturnError: pkgErrors.New("test")}, &err, Wrapf("closing"), WrapWithStack())
+
+		Convey("Its stacktrace should start from the caller", func() {
+			traces := Stacktrace(err)
+			So(traces[0], ShouldStartWith, "github.com/therne/errorist.TestWrapModes")
+			So(fmt.Sprintf("%+v", err), ShouldStartWith, "closing: test\n    github.com/therne/errorist.TestWrapModes")
+		})
+
+		Convey("Its fingerprint should be identical for the same site", func() {
+			var fingerprints []string
+			for i := 0; i < 2; i++ {
+				var err error
+				CloseWithErrCapture(&closerMock{ReturnError: fmt.Errorf("test %d", i)}, &err, Wrapf("closing"), WrapWithStack())
+				fingerprints = append(fingerprints, Fingerprint(err))
+			}
+			So(fingerprints[0], ShouldEqual, fingerprints[1])
+		})
+	})
+
+	Convey("Using both WrapWithFmtErrorf and WrapWithStack", t, func() {
+		Convey("The latter one should win", func() {
+			o := EffectiveOptions(WrapWithStack(), WrapWithFmtErrorf())
+			So(o.WrapWithFmtErrorf, ShouldBeTrue)
+			So(o.WrapWithStack, ShouldBeFalse)
+		})
+	})
+}
+
+type customError struct {
+	msg string
+}
+
+func (e *customError) Error() string {
+	return e.msg
+}
@@ -0,0 +1,88 @@
+// Package zaplog provides an errorist.ErrorLogger using go.uber.org/zap.
+//
+// Errors are checked against the level of the logger first, so nothing is encoded for disabled levels.
+package zaplog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/therne/errorist"
+)
+
+// LogWith is an option for using zap on functions end with "WithErrLog".
+// Please refer New for details.
+func LogWith(logger *zap.Logger) errorist.Option {
+	return errorist.WithErrorLogger(New(logger))
+}
+
+// New returns an errorist.ErrorLogger using zap. Errors are logged with a field "error"
+// containing its message, type, operation, resource type, caller, wrap context,
+// fingerprint and stacktrace, at the zap level mapped from their levels.
+func New(logger *zap.Logger) errorist.ErrorLogger {
+	return &zapLogger{logger: logger}
+}
+
+// Level returns the zap level corresponding to the errorist level.
+func Level(level errorist.Level) zapcore.Level {
+	switch level {
+	case errorist.LevelDebug:
+		return zapcore.DebugLevel
+	case errorist.LevelInfo:
+		return zapcore.InfoLevel
+	case errorist.LevelWarn:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+type zapLogger struct {
+	logger *zap.Logger
+}
+
+func (l *zapLogger) LogError(err error, meta errorist.LogMeta) {
+	summary := strings.SplitN(err.Error(), "\n", 2)[0]
+	if ce := l.logger.Check(Level(meta.Level), summary); ce != nil {
+		ce.Write(zap.Object("error", errorObject{err: err, meta: meta}))
+	}
+}
+
+// errorObject marshals an error with its log metadata as a zap object.
+type errorObject struct {
+	err  error
+	meta errorist.LogMeta
+}
+
+func (o errorObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("message", o.err.Error())
+	enc.AddString("type", fmt.Sprintf("%T", errors.Cause(o.err)))
+	enc.AddString("operation", string(o.meta.Operation))
+	if o.meta.ResourceType != "" {
+		enc.AddString("resource", o.meta.ResourceType)
+	}
+	if o.meta.Caller.Function != "" {
+		enc.AddString("caller", o.meta.Caller.String())
+	}
+	if o.meta.Wrap != "" {
+		enc.AddString("wrap", o.meta.Wrap)
+	}
+	enc.AddString("fingerprint", errorist.Fingerprint(o.err))
+	if frames := errorist.ErrorFrames(o.err); len(frames) > 0 {
+		return enc.AddArray("stack", stackArray(frames))
+	}
+	return nil
+}
+
+type stackArray []errorist.Frame
+
+func (s stackArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, frame := range s {
+		enc.AppendString(frame.String())
+	}
+	return nil
+}
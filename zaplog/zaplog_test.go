@@ -0,0 +1,70 @@
+package zaplog
+
+import (
+	"io"
+	"testing"
+
+	"github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/therne/errorist"
+)
+
+func TestZapLogger(t *testing.T) {
+	Convey("Logging errors with zap", t, func() {
+		core, logs := observer.New(zapcore.DebugLevel)
+		opt := LogWith(zap.New(core))
+
+		Convey("It should log errors with structured fields", func() {
+			errorist.CloseWithLogOnErr(&closerMock{ReturnError: errors.New("close failed")}, opt, errorist.Wrapf("closing %s", "db"))
+
+			So(logs.Len(), ShouldEqual, 1)
+			entry := logs.All()[0]
+			So(entry.Level, ShouldEqual, zapcore.WarnLevel)
+			So(entry.Message, ShouldEqual, "closing db: close failed")
+
+			fields := entry.ContextMap()["error"].(map[string]interface{})
+			So(fields["message"], ShouldStartWith, "closing db: close failed")
+			So(fields["type"], ShouldEqual, "*errors.fundamental")
+			So(fields["operation"], ShouldEqual, "close")
+			So(fields["resource"], ShouldEqual, "*zaplog.closerMock")
+			So(fields["caller"], ShouldContainSubstring, "zaplog_test.go")
+			So(fields["wrap"], ShouldEqual, "closing db")
+			So(fields["fingerprint"], ShouldNotBeEmpty)
+			So(fields["stack"], ShouldNotBeEmpty)
+		})
+
+		Convey("It should log recovered panics at error level", func() {
+			func() {
+				defer errorist.RecoverWithErrLog(opt)
+				panic("zap")
+			}()
+
+			So(logs.Len(), ShouldEqual, 1)
+			entry := logs.All()[0]
+			So(entry.Level, ShouldEqual, zapcore.ErrorLevel)
+			So(entry.Message, ShouldEqual, "panic: zap")
+
+			fields := entry.ContextMap()["error"].(map[string]interface{})
+			So(fields["operation"], ShouldEqual, "recover")
+			So(fields["stack"], ShouldNotBeEmpty)
+		})
+
+		Convey("It should respect levels of the logger", func() {
+			core, logs := observer.New(zapcore.ErrorLevel)
+			errorist.CloseWithLogOnErr(&closerMock{ReturnError: io.ErrUnexpectedEOF}, LogWith(zap.New(core)))
+			So(logs.Len(), ShouldEqual, 0)
+		})
+	})
+}
+
+type closerMock struct {
+	ReturnError error
+}
+
+func (c *closerMock) Close() error {
+	return c.ReturnError
+}
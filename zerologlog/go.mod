@@ -0,0 +1,23 @@
+module github.com/therne/errorist/zerologlog
+
+go 1.21
+
+require (
+	github.com/pkg/errors v0.9.1
+	github.com/rs/zerolog v1.33.0
+	github.com/smartystreets/goconvey v1.6.4
+	github.com/therne/errorist v0.0.0
+)
+
+require (
+	github.com/gopherjs/gopherjs v0.0.0-20181017120253-0766667cb4d1 // indirect
+	github.com/jtolds/gls v4.20.0+incompatible // indirect
+	github.com/maruel/panicparse v1.5.0 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
+	github.com/smartystreets/assertions v0.0.0-20180927180507-b2de0cb4f26d // indirect
+	golang.org/x/sys v0.12.0 // indirect
+)
+
+// resolved from the parent directory until errorist is tagged
+replace github.com/therne/errorist => ../
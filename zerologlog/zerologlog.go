@@ -0,0 +1,83 @@
+// Package zerologlog provides an errorist.ErrorLogger using github.com/rs/zerolog.
+//
+// Errors are written as a nested object, with the stacktrace as an array of strings.
+package zerologlog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+
+	"github.com/therne/errorist"
+)
+
+// LogWith is an option for using zerolog on functions end with "WithErrLog".
+// Please refer New for details.
+func LogWith(logger zerolog.Logger) errorist.Option {
+	return errorist.WithErrorLogger(New(logger))
+}
+
+// New returns an errorist.ErrorLogger using zerolog. Errors are logged with a field "error"
+// containing its message, type, operation, resource type, caller, wrap context,
+// fingerprint and stacktrace, at the zerolog level mapped from their levels.
+func New(logger zerolog.Logger) errorist.ErrorLogger {
+	return &zerologLogger{logger: logger}
+}
+
+// Level returns the zerolog level corresponding to the errorist level.
+func Level(level errorist.Level) zerolog.Level {
+	switch level {
+	case errorist.LevelDebug:
+		return zerolog.DebugLevel
+	case errorist.LevelInfo:
+		return zerolog.InfoLevel
+	case errorist.LevelWarn:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.ErrorLevel
+	}
+}
+
+type zerologLogger struct {
+	logger zerolog.Logger
+}
+
+func (l *zerologLogger) LogError(err error, meta errorist.LogMeta) {
+	e := l.logger.WithLevel(Level(meta.Level))
+	if e == nil {
+		return
+	}
+	summary := strings.SplitN(err.Error(), "\n", 2)[0]
+	e.Object("error", errorObject{err: err, meta: meta}).Msg(summary)
+}
+
+// errorObject marshals an error with its log metadata as a zerolog object.
+type errorObject struct {
+	err  error
+	meta errorist.LogMeta
+}
+
+func (o errorObject) MarshalZerologObject(e *zerolog.Event) {
+	e.Str("message", o.err.Error())
+	e.Str("type", fmt.Sprintf("%T", errors.Cause(o.err)))
+	e.Str("operation", string(o.meta.Operation))
+	if o.meta.ResourceType != "" {
+		e.Str("resource", o.meta.ResourceType)
+	}
+	if o.meta.Caller.Function != "" {
+		e.Str("caller", o.meta.Caller.String())
+	}
+	if o.meta.Wrap != "" {
+		e.Str("wrap", o.meta.Wrap)
+	}
+	e.Str("fingerprint", errorist.Fingerprint(o.err))
+	if frames := errorist.ErrorFrames(o.err); len(frames) > 0 {
+		stack := make([]string, len(frames))
+		for i, frame := range frames {
+			stack[i] = frame.String()
+		}
+		e.Strs("stack", stack)
+	}
+}
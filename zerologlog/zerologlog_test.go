@@ -0,0 +1,82 @@
+package zerologlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/therne/errorist"
+)
+
+func TestZerologLogger(t *testing.T) {
+	Convey("Logging errors with zerolog", t, func() {
+		buf := &bytes.Buffer{}
+		opt := LogWith(zerolog.New(buf))
+
+		Convey("It should log errors with structured fields", func() {
+			errorist.CloseWithLogOnErr(&closerMock{ReturnError: errors.New("close failed")}, opt, errorist.Wrapf("closing %s", "db"))
+
+			entries := decodeLogs(buf)
+			So(entries, ShouldHaveLength, 1)
+			So(entries[0]["level"], ShouldEqual, "warn")
+			So(entries[0]["message"], ShouldEqual, "closing db: close failed")
+
+			fields := entries[0]["error"].(map[string]interface{})
+			So(fields["message"], ShouldStartWith, "closing db: close failed")
+			So(fields["type"], ShouldEqual, "*errors.fundamental")
+			So(fields["operation"], ShouldEqual, "close")
+			So(fields["resource"], ShouldEqual, "*zerologlog.closerMock")
+			So(fields["caller"], ShouldContainSubstring, "zerologlog_test.go")
+			So(fields["wrap"], ShouldEqual, "closing db")
+			So(fields["fingerprint"], ShouldNotBeEmpty)
+			So(fields["stack"], ShouldNotBeEmpty)
+		})
+
+		Convey("It should log recovered panics at error level", func() {
+			func() {
+				defer errorist.RecoverWithErrLog(opt)
+				panic("zerolog")
+			}()
+
+			entries := decodeLogs(buf)
+			So(entries, ShouldHaveLength, 1)
+			So(entries[0]["level"], ShouldEqual, "error")
+			So(entries[0]["message"], ShouldEqual, "panic: zerolog")
+
+			fields := entries[0]["error"].(map[string]interface{})
+			So(fields["operation"], ShouldEqual, "recover")
+			So(fields["stack"], ShouldNotBeEmpty)
+		})
+
+		Convey("It should respect levels of the logger", func() {
+			logger := zerolog.New(buf).Level(zerolog.ErrorLevel)
+			errorist.CloseWithLogOnErr(&closerMock{ReturnError: io.ErrUnexpectedEOF}, LogWith(logger))
+			So(buf.Len(), ShouldEqual, 0)
+		})
+	})
+}
+
+func decodeLogs(buf *bytes.Buffer) (entries []map[string]interface{}) {
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		entry := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			panic(err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+type closerMock struct {
+	ReturnError error
+}
+
+func (c *closerMock) Close() error {
+	return c.ReturnError
+}